@@ -0,0 +1,79 @@
+package sf
+
+// Backend carries out the low-level drawing work of a RenderTarget.
+// RenderTarget keeps track of the view, the render states and its state cache,
+// and only tells the backend about the states that actually changed.
+type Backend interface {
+	// Activate makes the backend's surface the destination of the next
+	// commands. It reports whether another surface was the destination until
+	// then, in which case the states set before must be set again. If it
+	// fails, e.g. without an OpenGL context, the commands are skipped.
+	Activate() (bool, error)
+
+	// Clear fills the whole surface with a color
+	Clear(color Color)
+
+	// ClearStencil empties the stencil mask of the whole surface
+	ClearStencil()
+
+	// ResetStates sets the backend's persistent states to their defaults
+	ResetStates()
+
+	// PushStates saves the backend's current states, PopStates restores them
+	PushStates()
+	PopStates()
+
+	// SetViewport sets the area of the surface to draw into, in pixels, with
+	// the origin at the bottom-left corner (as glViewport expects it)
+	SetViewport(x, y, w, h int)
+
+	// SetScissor restricts drawing to a rectangle in pixels, with the origin
+	// at the bottom-left corner, or disables the restriction
+	SetScissor(enabled bool, x, y, w, h int)
+
+	// SetStencilMode sets how the next draws use the stencil mask
+	SetStencilMode(mode StencilMode)
+
+	// SetProjection sets the projection matrix, SetModelView the matrix applied
+	// to the vertices before projecting them
+	SetProjection(transform Transform)
+	SetModelView(transform Transform)
+
+	// SetBlendMode sets how the drawn pixels are combined with the surface
+	SetBlendMode(mode BlendMode)
+
+	// BindTexture binds the texture used by the next draws, with texture
+	// coordinates expressed in pixels. A nil texture unbinds.
+	BindTexture(texture *Texture)
+
+	// BindShader binds the shader used by the next draws. A nil shader unbinds.
+	BindShader(shader *Shader)
+
+	// DrawPrimitives draws the vertices with the current states
+	DrawPrimitives(verts []Vertex, primType PrimitiveType)
+
+	// DrawBuffer draws count vertices of a vertex buffer, starting at first,
+	// with the current states
+	DrawBuffer(vb *VertexBuffer, first, count int, primType PrimitiveType)
+}
+
+// Backend of closed windows and destroyed render textures, drawing nothing
+type closedBackend struct{}
+
+func (closedBackend) Activate() (bool, error)                               { return false, nil }
+func (closedBackend) Clear(color Color)                                     {}
+func (closedBackend) ClearStencil()                                         {}
+func (closedBackend) ResetStates()                                          {}
+func (closedBackend) PushStates()                                           {}
+func (closedBackend) PopStates()                                            {}
+func (closedBackend) SetViewport(x, y, w, h int)                            {}
+func (closedBackend) SetScissor(enabled bool, x, y, w, h int)               {}
+func (closedBackend) SetStencilMode(mode StencilMode)                       {}
+func (closedBackend) SetProjection(transform Transform)                     {}
+func (closedBackend) SetModelView(transform Transform)                      {}
+func (closedBackend) SetBlendMode(mode BlendMode)                           {}
+func (closedBackend) BindTexture(texture *Texture)                          {}
+func (closedBackend) BindShader(shader *Shader)                             {}
+func (closedBackend) DrawPrimitives(verts []Vertex, primType PrimitiveType) {}
+func (closedBackend) DrawBuffer(vb *VertexBuffer, first, count int, primType PrimitiveType) {
+}
@@ -0,0 +1,85 @@
+package sf
+
+// BlendFactor is what the source or destination color is multiplied by
+// before they're combined
+type BlendFactor uint8
+
+const (
+	FactorZero             BlendFactor = iota // (0, 0, 0, 0)
+	FactorOne                                 // (1, 1, 1, 1)
+	FactorSrcColor                            // (src.r, src.g, src.b, src.a)
+	FactorOneMinusSrcColor                    // (1, 1, 1, 1) - (src.r, src.g, src.b, src.a)
+	FactorDstColor                            // (dst.r, dst.g, dst.b, dst.a)
+	FactorOneMinusDstColor                    // (1, 1, 1, 1) - (dst.r, dst.g, dst.b, dst.a)
+	FactorSrcAlpha                            // (src.a, src.a, src.a, src.a)
+	FactorOneMinusSrcAlpha                    // (1, 1, 1, 1) - (src.a, src.a, src.a, src.a)
+	FactorDstAlpha                            // (dst.a, dst.a, dst.a, dst.a)
+	FactorOneMinusDstAlpha                    // (1, 1, 1, 1) - (dst.a, dst.a, dst.a, dst.a)
+)
+
+// BlendEquation is how the source and destination colors are combined, once
+// multiplied by their factors
+type BlendEquation uint8
+
+const (
+	EquationAdd             BlendEquation = iota // Pixel = Src * SrcFactor + Dst * DstFactor
+	EquationSubtract                             // Pixel = Src * SrcFactor - Dst * DstFactor
+	EquationReverseSubtract                      // Pixel = Dst * DstFactor - Src * SrcFactor
+	EquationMin                                  // Pixel = min(Dst, Src), the factors are ignored
+	EquationMax                                  // Pixel = max(Dst, Src), the factors are ignored
+)
+
+// BlendMode tells how the drawn pixels are combined with the pixels of the
+// target, with separate factors and equations for the color and alpha
+// channels.
+//
+// The zero BlendMode would erase everything drawn, it's used as BlendAlpha
+// instead, so that zero RenderStates draw with alpha blending. To erase, use
+// NewBlendMode(FactorZero, FactorZero, EquationSubtract), which has the same
+// effect.
+type BlendMode struct {
+	ColorSrcFactor BlendFactor   // Source blending factor for the color channels
+	ColorDstFactor BlendFactor   // Destination blending factor for the color channels
+	ColorEquation  BlendEquation // Blending equation for the color channels
+	AlphaSrcFactor BlendFactor   // Source blending factor for the alpha channel
+	AlphaDstFactor BlendFactor   // Destination blending factor for the alpha channel
+	AlphaEquation  BlendEquation // Blending equation for the alpha channel
+}
+
+// NewBlendMode returns a blend mode using the same factors and equation for
+// the color and alpha channels
+func NewBlendMode(srcFactor, dstFactor BlendFactor, equation BlendEquation) BlendMode {
+	return BlendMode{srcFactor, dstFactor, equation, srcFactor, dstFactor, equation}
+}
+
+// BlendAlpha returns the default blend mode, Pixel = Src * Src.a + Dst *
+// (1 - Src.a). The alpha accumulates so that render textures stay opaque
+// where something opaque was drawn.
+func BlendAlpha() BlendMode {
+	return BlendMode{FactorSrcAlpha, FactorOneMinusSrcAlpha, EquationAdd,
+		FactorOne, FactorOneMinusSrcAlpha, EquationAdd}
+}
+
+// BlendAdd returns the additive blend mode: Pixel = Src * Src.a + Dst
+func BlendAdd() BlendMode {
+	return BlendMode{FactorSrcAlpha, FactorOne, EquationAdd,
+		FactorOne, FactorOne, EquationAdd}
+}
+
+// BlendMultiply returns the multiplicative blend mode: Pixel = Src * Dst
+func BlendMultiply() BlendMode {
+	return NewBlendMode(FactorDstColor, FactorZero, EquationAdd)
+}
+
+// BlendNone returns the overwriting blend mode: Pixel = Src
+func BlendNone() BlendMode {
+	return NewBlendMode(FactorOne, FactorZero, EquationAdd)
+}
+
+// orDefault returns BlendAlpha for the zero BlendMode, the mode itself otherwise
+func (mode BlendMode) orDefault() BlendMode {
+	if mode == (BlendMode{}) {
+		return BlendAlpha()
+	}
+	return mode
+}
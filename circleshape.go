@@ -0,0 +1,47 @@
+package sf
+
+import (
+	"math"
+)
+
+// CircleShape is a Shape approximating a circle with a regular polygon. Its
+// local origin is the top-left corner of its bounding box, not its center.
+type CircleShape struct {
+	Shape
+	radius float32
+}
+
+// NewCircleShape creates a circle made of pointCount points. 30 is a good
+// default, more make it smoother.
+func NewCircleShape(radius float32, pointCount int) *CircleShape {
+	c := &CircleShape{radius: radius}
+	c.init()
+	c.SetPointCount(pointCount)
+	return c
+}
+
+func (c *CircleShape) SetRadius(radius float32) {
+	c.radius = radius
+	c.SetPointCount(c.PointCount())
+}
+
+func (c *CircleShape) Radius() float32 {
+	return c.radius
+}
+
+// SetPointCount sets the number of points approximating the circle. A
+// negative count is taken as 0.
+func (c *CircleShape) SetPointCount(count int) {
+	if count < 0 {
+		count = 0
+	}
+	points := make([]Vector2, count)
+	for i := range points {
+		// Start at the top, like SFML
+		angle := float64(i)*2*math.Pi/float64(count) - math.Pi/2
+		points[i] = Vector2{
+			c.radius + float32(math.Cos(angle))*c.radius,
+			c.radius + float32(math.Sin(angle))*c.radius}
+	}
+	c.update(points)
+}
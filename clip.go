@@ -0,0 +1,123 @@
+package sf
+
+import (
+	"math"
+)
+
+// Clip restricts drawing to a rectangle, with the OpenGL scissor test
+type Clip struct {
+	Rect   Rect // Area drawing is restricted to
+	Pixels bool // Is Rect in pixels of the target, instead of coordinates of the current view?
+}
+
+// PushClip restricts the following draws to a rectangle, intersected with the
+// clips already pushed. Clips in view coordinates are mapped through the view
+// current when drawing. It must be paired with PopClip.
+func (r *RenderTarget) PushClip(clip Clip) {
+	r.clips = append(r.clips, clip)
+}
+
+// PopClip removes the clip pushed last
+func (r *RenderTarget) PopClip() {
+	if len(r.clips) > 0 {
+		r.clips = r.clips[:len(r.clips)-1]
+	}
+}
+
+// MapCoordsToPixel converts a point from the coordinates of a view to pixels
+// of the target, with the origin at the top-left corner
+func (r *RenderTarget) MapCoordsToPixel(point Vector2, view *View) Vector2 {
+	// First, transform the point by the view matrix
+	transform := view.Transform()
+	normalized := transform.TransformPoint(point)
+
+	// Then convert to viewport coordinates, in whole pixels like the viewport
+	// is applied
+	viewport := r.Viewport(view)
+	viewport = Rect{float32(int(viewport.Left)), float32(int(viewport.Top)),
+		float32(int(viewport.W)), float32(int(viewport.H))}
+	return Vector2{
+		(normalized.X+1)/2*viewport.W + viewport.Left,
+		(-normalized.Y+1)/2*viewport.H + viewport.Top}
+}
+
+// resolveClip combines the clip stack and the clip of a draw into a clip in
+// whole pixels, nil if there's no clipping
+func (r *RenderTarget) resolveClip(clip *Clip) *Clip {
+	if clip == nil && len(r.clips) == 0 {
+		return nil
+	}
+
+	left, top := float32(math.Inf(-1)), float32(math.Inf(-1))
+	right, bottom := float32(math.Inf(1)), float32(math.Inf(1))
+	intersect := func(c Clip) {
+		rect := c.Rect
+		if !c.Pixels {
+			rect = r.pixelRect(rect)
+		}
+		left = max32(left, rect.Left)
+		top = max32(top, rect.Top)
+		right = min32(right, rect.Left+rect.W)
+		bottom = min32(bottom, rect.Top+rect.H)
+	}
+	for _, c := range r.clips {
+		intersect(c)
+	}
+	if clip != nil {
+		intersect(*clip)
+	}
+
+	// The scissor box is in whole pixels
+	left, top = round32(left), round32(top)
+	right, bottom = max32(left, round32(right)), max32(top, round32(bottom))
+	return &Clip{Rect{left, top, right - left, bottom - top}, true}
+}
+
+// pixelRect returns the bounding rectangle in pixels of a rectangle in
+// coordinates of the current view
+func (r *RenderTarget) pixelRect(rect Rect) Rect {
+	corners := []Vector2{
+		r.MapCoordsToPixel(Vector2{rect.Left, rect.Top}, r.view),
+		r.MapCoordsToPixel(Vector2{rect.Left + rect.W, rect.Top}, r.view),
+		r.MapCoordsToPixel(Vector2{rect.Left, rect.Top + rect.H}, r.view),
+		r.MapCoordsToPixel(Vector2{rect.Left + rect.W, rect.Top + rect.H}, r.view),
+	}
+	return boundingRect(corners)
+}
+
+// Scissor box set in the backend, with the origin at the bottom-left corner
+type scissorBox struct {
+	enabled    bool
+	x, y, w, h int
+}
+
+// scissorBox returns the scissor box of a clip in pixels, which depends on
+// the size of the target
+func (r *RenderTarget) scissorBox(clip *Clip) scissorBox {
+	if clip == nil {
+		return scissorBox{}
+	}
+
+	// Convert to a bottom-left origin
+	rect := clip.Rect
+	bottom := r.size.Y - (rect.Top + rect.H)
+	return scissorBox{true, int(rect.Left), int(bottom), int(rect.W), int(rect.H)}
+}
+
+func (r *RenderTarget) applyClip(clip *Clip) {
+	box := r.scissorBox(clip)
+	r.backend.SetScissor(box.enabled, box.x, box.y, box.w, box.h)
+	r.lastScissor = box
+}
+
+// sameClip tells if two clips restrict drawing the same way
+func sameClip(c1, c2 *Clip) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	}
+	return *c1 == *c2
+}
+
+func round32(x float32) float32 {
+	return float32(math.Floor(float64(x) + 0.5))
+}
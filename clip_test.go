@@ -0,0 +1,109 @@
+package sf
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestClip(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	full := quad(-10, -10, 100, 100, Color{255, 255, 255, 255})
+	draw := func(target *RenderTarget, clip *Clip) {
+		target.Clear(Color{0, 0, 0, 255})
+		states := DefaultRenderStates()
+		states.Clip = clip
+		target.Render(full, Quads, states)
+	}
+
+	target, img := newSoftwareTarget(8, 8)
+
+	// In pixels
+	draw(target, &Clip{Rect{2, 2, 3, 3}, true})
+	if got := countPixels(img, white); got != 9 || img.RGBAAt(2, 2) != white || img.RGBAAt(5, 5) == white {
+		t.Errorf("pixel clip: %d pixels drawn", got)
+	}
+
+	// In coordinates of a zoomed view
+	view := NewView()
+	view.Reset(Rect{0, 0, 4, 4})
+	target.SetView(*view)
+	draw(target, &Clip{Rect{1, 1, 1, 1}, false})
+	if got := countPixels(img, white); got != 4 || img.RGBAAt(2, 2) != white || img.RGBAAt(3, 3) != white {
+		t.Errorf("view clip: %d pixels drawn", got)
+	}
+	target.SetView(target.DefaultView())
+
+	// Intersected with the stack
+	target.PushClip(Clip{Rect{0, 0, 4, 4}, true})
+	draw(target, &Clip{Rect{2, 2, 4, 4}, true})
+	if got := countPixels(img, white); got != 4 {
+		t.Errorf("stacked clip: %d pixels drawn", got)
+	}
+	draw(target, nil)
+	if got := countPixels(img, white); got != 16 {
+		t.Errorf("stack only: %d pixels drawn", got)
+	}
+
+	// Disjoint clips draw nothing
+	draw(target, &Clip{Rect{6, 6, 2, 2}, true})
+	if got := countPixels(img, white); got != 0 {
+		t.Errorf("disjoint clips: %d pixels drawn", got)
+	}
+
+	target.PopClip()
+	draw(target, nil)
+	if got := countPixels(img, white); got != 64 {
+		t.Errorf("no clip: %d pixels drawn", got)
+	}
+}
+
+func TestClipBatching(t *testing.T) {
+	target, img := newSoftwareTarget(8, 8)
+	target.SetBatching(true)
+	target.Clear(Color{0, 0, 0, 255})
+
+	states := DefaultRenderStates()
+	states.Clip = &Clip{Rect{0, 0, 4, 8}, true}
+	target.Render(quad(0, 0, 8, 2, Color{255, 0, 0, 255}), Quads, states)
+	states.Clip = &Clip{Rect{0, 0, 4, 8}, true}
+	target.Render(quad(0, 2, 8, 2, Color{255, 0, 0, 255}), Quads, states)
+
+	// Another clip can't be merged
+	states.Clip = &Clip{Rect{4, 0, 4, 8}, true}
+	target.Render(quad(0, 4, 8, 2, Color{0, 255, 0, 255}), Quads, states)
+	target.Flush()
+
+	if stats := target.Stats(); stats.Batches != 2 || stats.BatchedDraws != 3 {
+		t.Errorf("stats: %+v", stats)
+	}
+	if countPixels(img, color.RGBA{255, 0, 0, 255}) != 16 || countPixels(img, color.RGBA{0, 255, 0, 255}) != 8 {
+		t.Error("clips not applied to the batches")
+	}
+}
+
+// Backend recording the scissor boxes set
+type scissorRecorder struct {
+	Backend
+	boxes []scissorBox
+}
+
+func (b *scissorRecorder) SetScissor(enabled bool, x, y, w, h int) {
+	b.boxes = append(b.boxes, scissorBox{enabled, x, y, w, h})
+	b.Backend.SetScissor(enabled, x, y, w, h)
+}
+
+func TestClipResize(t *testing.T) {
+	backend := &scissorRecorder{Backend: NewSoftwareBackend(image.NewRGBA(image.Rect(0, 0, 8, 16)))}
+	target := NewRenderTargetWithBackend(Vector2{8, 8}, backend)
+	states := DefaultRenderStates()
+	states.Clip = &Clip{Rect{0, 0, 8, 2}, true}
+	target.Render(quad(0, 0, 8, 8, Color{255, 255, 255, 255}), Quads, states)
+
+	// The same clip is at another distance from the bottom of the target
+	target.SetSize(Vector2{8, 16})
+	target.Render(quad(0, 0, 8, 8, Color{255, 255, 255, 255}), Quads, states)
+	if got := backend.boxes[len(backend.boxes)-1]; got != (scissorBox{true, 0, 14, 8, 2}) {
+		t.Errorf("scissor box after resize: %+v", got)
+	}
+}
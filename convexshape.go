@@ -0,0 +1,35 @@
+package sf
+
+// ConvexShape is a Shape made of arbitrary points. They must form a convex
+// polygon, given in order (clockwise or counter-clockwise), otherwise the
+// result is undefined.
+type ConvexShape struct {
+	Shape
+}
+
+// NewConvexShape creates a convex shape with pointCount points, all at (0, 0)
+func NewConvexShape(pointCount int) *ConvexShape {
+	c := &ConvexShape{}
+	c.init()
+	c.SetPointCount(pointCount)
+	return c
+}
+
+// SetPointCount sets the number of points, keeping the existing ones. New
+// points are at (0, 0). A negative count is taken as 0.
+func (c *ConvexShape) SetPointCount(count int) {
+	if count < 0 {
+		count = 0
+	}
+	points := make([]Vector2, count)
+	copy(points, c.points)
+	c.update(points)
+}
+
+// SetPoint moves one of the points
+func (c *ConvexShape) SetPoint(index int, point Vector2) {
+	points := make([]Vector2, len(c.points))
+	copy(points, c.points)
+	points[index] = point
+	c.update(points)
+}
@@ -0,0 +1,25 @@
+package sf
+
+// Drawable is anything that can draw itself into a render target: sprites,
+// shapes, text, vertex arrays, or user types.
+type Drawable interface {
+	// Render draws the object, combining its own states (transform,
+	// texture...) with the ones given
+	Render(target *RenderTarget, states RenderStates)
+}
+
+// DefaultRenderStates returns the states used to draw when none are given:
+// alpha blending, identity transform, no texture and no shader.
+func DefaultRenderStates() RenderStates {
+	return RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+}
+
+// Draw draws a drawable with the given states, or with DefaultRenderStates if
+// none are given
+func (r *RenderTarget) Draw(d Drawable, states ...RenderStates) {
+	if len(states) > 0 {
+		d.Render(r, states[0])
+	} else {
+		d.Render(r, DefaultRenderStates())
+	}
+}
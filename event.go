@@ -0,0 +1,78 @@
+package sf
+
+// Event is an event received by a RenderWindow. It holds one of the *Event
+// types below; use a type switch to tell them apart.
+type Event interface{}
+
+// The window requested to be closed
+type ClosedEvent struct{}
+
+// The window was resized. Width and Height are the new size of its
+// framebuffer, in pixels.
+type ResizedEvent struct {
+	Width  int
+	Height int
+}
+
+// The window gained the focus
+type FocusGainedEvent struct{}
+
+// The window lost the focus
+type FocusLostEvent struct{}
+
+// A character was entered
+type TextEnteredEvent struct {
+	Char rune // UTF-32 code point of the character
+}
+
+// Parameters of the KeyPressed and KeyReleased events
+type KeyEvent struct {
+	Code     Key  // Code of the key
+	Scancode int  // Platform-specific code of the key
+	Alt      bool // Is the Alt key pressed?
+	Control  bool // Is the Control key pressed?
+	Shift    bool // Is the Shift key pressed?
+	System   bool // Is the System key (Windows, Command, Super) pressed?
+}
+
+// A key was pressed, or is repeating because it's held down
+type KeyPressedEvent struct {
+	KeyEvent
+}
+
+// A key was released
+type KeyReleasedEvent struct {
+	KeyEvent
+}
+
+// The mouse cursor moved. X and Y are relative to the top-left corner of the
+// window.
+type MouseMovedEvent struct {
+	X int
+	Y int
+}
+
+// Parameters of the MouseButtonPressed and MouseButtonReleased events
+type MouseButtonEvent struct {
+	Button MouseButton // Code of the button
+	X      int         // X position of the cursor, relative to the left of the window
+	Y      int         // Y position of the cursor, relative to the top of the window
+}
+
+// A mouse button was pressed
+type MouseButtonPressedEvent struct {
+	MouseButtonEvent
+}
+
+// A mouse button was released
+type MouseButtonReleasedEvent struct {
+	MouseButtonEvent
+}
+
+// The mouse wheel (or the touchpad) scrolled
+type MouseWheelEvent struct {
+	DeltaX float32 // Horizontal offset, positive to the right
+	DeltaY float32 // Vertical offset, positive upwards
+	X      int     // X position of the cursor, relative to the left of the window
+	Y      int     // Y position of the cursor, relative to the top of the window
+}
@@ -2,27 +2,27 @@ package main
 
 import (
 	"fmt"
-	"github.com/go-gl/glfw3/v3.1/glfw"
 	"github.com/tedsta/gosfml"
 )
 
 var (
-	window           *glfw.Window
-	target           *sf.RenderTarget
+	window           *sf.RenderWindow
 	p1, p2, ball     *Object
 	p1Score, p2Score int
 )
 
 func main() {
-	window, err := glfw.CreateWindow(800, 600, "Golang SFML Pong", nil, nil)
-	if err != nil {
+	if err := sf.Init(); err != nil {
 		panic(err)
 	}
-	window.MakeContextCurrent()
-	window.SetFramebufferSizeCallback(onResize)
-	window.SetKeyCallback(onKey)
+	defer sf.Terminate()
 
-	target = sf.NewRenderTarget(sf.Vector2{float32(800), float32(600)})
+	var err error
+	window, err = sf.NewRenderWindow(800, 600, "Golang SFML Pong")
+	if err != nil {
+		panic(err)
+	}
+	window.SetResizePolicy(sf.ResizeLetterbox)
 
 	p1 = NewObject(5, 5, 16, 64)
 	p2 = NewObject(795-16, 5, 16, 64)
@@ -31,8 +31,10 @@ func main() {
 	ball.vel = sf.Vector2{300, 300}
 
 	clock := sf.NewClock()
-	for !window.ShouldClose() {
-		glfw.PollEvents()
+	for window.IsOpen() {
+		for event, ok := window.PollEvent(); ok; event, ok = window.PollEvent() {
+			onEvent(event)
+		}
 
 		dt := float32(clock.Restart().Seconds())
 
@@ -72,11 +74,11 @@ func main() {
 		ball.pos.Y += ball.vel.Y * dt
 
 		// Render
-		target.Clear(sf.Color{0, 0, 0, 0})
-		p1.Render()
-		p2.Render()
-		ball.Render()
-		window.SwapBuffers()
+		window.Clear(sf.Color{0, 0, 0, 0})
+		window.Draw(p1)
+		window.Draw(p2)
+		window.Draw(ball)
+		window.Display()
 	}
 }
 
@@ -84,13 +86,15 @@ func main() {
 // Object
 
 type Object struct {
-	pos sf.Vector2
-	dim sf.Vector2
-	vel sf.Vector2
+	pos   sf.Vector2
+	dim   sf.Vector2
+	vel   sf.Vector2
+	shape *sf.RectangleShape
 }
 
 func NewObject(x, y, w, h float32) *Object {
-	return &Object{sf.Vector2{x, y}, sf.Vector2{w, h}, sf.Vector2{}}
+	dim := sf.Vector2{w, h}
+	return &Object{sf.Vector2{x, y}, dim, sf.Vector2{}, sf.NewRectangleShape(dim)}
 }
 
 func (o *Object) Collision(o2 *Object) bool {
@@ -101,55 +105,34 @@ func (o *Object) Collision(o2 *Object) bool {
 	return false
 }
 
-func (o *Object) Render() {
-	var verts [4]sf.Vertex
-	verts[0] = sf.Vertex{sf.Vector2{},
-		sf.Color{255, 255, 255, 255},
-		sf.Vector2{}}
-	verts[1] = sf.Vertex{sf.Vector2{0, o.dim.Y},
-		sf.Color{255, 255, 255, 255},
-		sf.Vector2{0, o.dim.Y}}
-	verts[2] = sf.Vertex{o.dim,
-		sf.Color{255, 255, 255, 255},
-		o.dim}
-	verts[3] = sf.Vertex{sf.Vector2{o.dim.X, 0},
-		sf.Color{255, 255, 255, 255},
-		sf.Vector2{o.dim.X, 0}}
-
-	states := sf.RenderStates{sf.BlendAlpha, sf.IdentityTransform(), nil}
-	states.Transform.Translate(o.pos)
-	target.Render(verts[:], sf.Quads, states)
+func (o *Object) Render(target *sf.RenderTarget, states sf.RenderStates) {
+	o.shape.SetPosition(o.pos)
+	o.shape.Render(target, states)
 }
 
 // #############################################################################
-// Callbacks
-
-func onResize(wnd *glfw.Window, w, h int) {
-	tmp1 := target.Size()
-	tmp1.X = float32(w)
-	tmp1.Y = float32(h)
-
-	//target.Size.X = float32(w)
-	//target.Size.Y = float32(h)
-}
-
-func onKey(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	if action == glfw.Press {
-		switch key {
-		case glfw.KeyW:
+// Events
+
+func onEvent(event sf.Event) {
+	switch e := event.(type) {
+	case sf.ClosedEvent:
+		window.Close()
+	case sf.KeyPressedEvent:
+		switch e.Code {
+		case sf.KeyW:
 			p1.vel.Y = -300
-		case glfw.KeyS:
+		case sf.KeyS:
 			p1.vel.Y = 300
-		case glfw.KeyUp:
+		case sf.KeyUp:
 			p2.vel.Y = -300
-		case glfw.KeyDown:
+		case sf.KeyDown:
 			p2.vel.Y = 300
 		}
-	} else if action == glfw.Release {
-		switch key {
-		case glfw.KeyW, glfw.KeyS:
+	case sf.KeyReleasedEvent:
+		switch e.Code {
+		case sf.KeyW, sf.KeyS:
 			p1.vel.Y = 0
-		case glfw.KeyUp, glfw.KeyDown:
+		case sf.KeyUp, sf.KeyDown:
 			p2.vel.Y = 0
 		}
 	}
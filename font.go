@@ -0,0 +1,282 @@
+package sf
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Glyph describes a character rasterized by a Font
+type Glyph struct {
+	Advance     float32 // Horizontal offset to the next character
+	Bounds      Rect    // Bounding rectangle of the glyph, relative to the baseline
+	TextureRect Rect    // Rectangle of the glyph in the font's texture
+}
+
+// FontInfo holds general information about a font
+type FontInfo struct {
+	Family string // Family name of the font
+}
+
+// Font loads TrueType and OpenType fonts, or wraps a bitmap font.Face. Glyphs
+// are rasterized on demand for each character size into a texture that grows
+// as needed.
+type Font struct {
+	font  *sfnt.Font         // Outlines of the font, nil for bitmap fonts
+	face  font.Face          // Face of bitmap fonts, used for every character size
+	info  FontInfo           // Information about the font
+	pages map[uint]*fontPage // Glyphs and texture of each character size
+}
+
+// Set of glyphs rasterized for a character size
+type fontPage struct {
+	face    font.Face          // Face rasterizing the glyphs
+	glyphs  map[glyphKey]Glyph // Glyphs already in the texture
+	image   *image.NRGBA       // Pixels of the texture
+	texture *Texture           // Texture holding the glyphs
+	rows    []fontRow          // Rows of glyphs in the texture
+	nextRow int                // Y position of the next row to create
+}
+
+type glyphKey struct {
+	codePoint rune
+	bold      bool
+}
+
+// Row of glyphs in a page's texture
+type fontRow struct {
+	top    int // Y position of the row in the texture
+	height int // Height of the row
+	width  int // Width used so far
+}
+
+// Initial size of a page's texture
+const fontPageSize = 128
+
+// NewFontFromFile loads a TrueType or OpenType font file
+func NewFontFromFile(fname string) (*Font, error) {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := NewFontFromMemory(data)
+	if err != nil {
+		return nil, fmt.Errorf("sf: %s: %v", fname, err)
+	}
+	return f, nil
+}
+
+// NewFontFromMemory loads a TrueType or OpenType font from the content of a
+// font file. The data must not be modified while the font is in use.
+func NewFontFromMemory(data []byte) (*Font, error) {
+	otf, err := opentype.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Font{font: otf, pages: make(map[uint]*fontPage)}
+	f.info.Family, _ = otf.Name(nil, sfnt.NameIDFamily)
+	return f, nil
+}
+
+// NewFontFromFace wraps a bitmap font, e.g. from golang.org/x/image/font/basicfont
+// or plan9font. It has a single size: the character size is ignored.
+func NewFontFromFace(face font.Face) *Font {
+	return &Font{face: face, pages: make(map[uint]*fontPage)}
+}
+
+func (f *Font) Info() FontInfo {
+	return f.info
+}
+
+// Glyph returns a character at the given size, rasterizing it if it's the
+// first time it's requested. Bold glyphs are emboldened from the regular ones.
+func (f *Font) Glyph(codePoint rune, characterSize uint, bold bool) Glyph {
+	page := f.page(characterSize)
+
+	key := glyphKey{codePoint, bold}
+	if glyph, ok := page.glyphs[key]; ok {
+		return glyph
+	}
+
+	glyph := page.loadGlyph(codePoint, bold)
+	page.glyphs[key] = glyph
+	return glyph
+}
+
+// Kerning returns the offset to add to the advance of first when it's followed
+// by second
+func (f *Font) Kerning(first, second rune, characterSize uint) float32 {
+	if first == 0 || second == 0 {
+		return 0
+	}
+	return fixedToFloat(f.page(characterSize).face.Kern(first, second))
+}
+
+// LineSpacing returns the vertical offset between two lines of text
+func (f *Font) LineSpacing(characterSize uint) float32 {
+	return fixedToFloat(f.page(characterSize).face.Metrics().Height)
+}
+
+// Ascent returns the distance from the baseline to the top of the tallest
+// glyphs
+func (f *Font) Ascent(characterSize uint) float32 {
+	return fixedToFloat(f.page(characterSize).face.Metrics().Ascent)
+}
+
+// UnderlinePosition returns the distance from the baseline to the middle of
+// the underline
+func (f *Font) UnderlinePosition(characterSize uint) float32 {
+	return float32(f.size(characterSize)) / 10
+}
+
+// UnderlineThickness returns the thickness of the underline
+func (f *Font) UnderlineThickness(characterSize uint) float32 {
+	return float32(f.size(characterSize)) / 14
+}
+
+// Texture returns the texture holding the glyphs of the given size. It grows
+// and changes as new glyphs are requested.
+func (f *Font) Texture(characterSize uint) *Texture {
+	return f.page(characterSize).texture
+}
+
+// size returns the actual character size of the font
+func (f *Font) size(characterSize uint) uint {
+	if f.face != nil {
+		return uint(f.face.Metrics().Height.Ceil())
+	}
+	return characterSize
+}
+
+// page returns the page of a character size, creating it if needed
+func (f *Font) page(characterSize uint) *fontPage {
+	// Bitmap fonts only have one size
+	if f.face != nil {
+		characterSize = 0
+	}
+
+	if page, ok := f.pages[characterSize]; ok {
+		return page
+	}
+
+	face := f.face
+	if face == nil {
+		var err error
+		face, err = opentype.NewFace(f.font, &opentype.FaceOptions{
+			Size:    float64(characterSize),
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			// Only happens with invalid options
+			panic(err)
+		}
+	}
+
+	// Reserve a white square for the lines drawn with the glyphs (e.g. the
+	// underline), so that they can share the texture
+	img := image.NewNRGBA(image.Rect(0, 0, fontPageSize, fontPageSize))
+	draw.Draw(img, image.Rect(0, 0, 2, 2), image.White, image.Point{}, draw.Src)
+	page := &fontPage{face: face, glyphs: make(map[glyphKey]Glyph), image: img, texture: newTexture(img), nextRow: 3}
+	page.texture.keepPixels = true // The glyphs are drawn into img
+	f.pages[characterSize] = page
+	return page
+}
+
+// loadGlyph rasterizes a glyph into the page's texture
+func (p *fontPage) loadGlyph(codePoint rune, bold bool) Glyph {
+	var glyph Glyph
+
+	dr, mask, maskp, advance, _ := p.face.Glyph(fixed.Point26_6{}, codePoint)
+	glyph.Advance = fixedToFloat(advance)
+	if mask == nil || dr.Empty() {
+		// Nothing to draw, e.g. a space
+		return glyph
+	}
+
+	// Emboldening smears the glyph to the right
+	var offset int
+	if bold {
+		offset = 1 + dr.Dy()/16
+		glyph.Advance += float32(offset)
+	}
+
+	// Leave a pixel between glyphs so that they don't bleed into each other
+	const padding = 1
+	w, h := dr.Dx()+offset, dr.Dy()
+	x, y := p.findRect(w+2*padding, h+2*padding)
+	x, y = x+padding, y+padding
+
+	// Invalidate first, the pending batches are drawn with the texture as it was
+	p.texture.invalidate(image.Rect(x, y, x+w, y+h))
+	for i := 0; i <= offset; i++ {
+		r := image.Rect(x+i, y, x+i+dr.Dx(), y+h)
+		draw.DrawMask(p.image, r, image.White, image.Point{}, mask, maskp, draw.Over)
+	}
+
+	glyph.Bounds = Rect{float32(dr.Min.X), float32(dr.Min.Y), float32(w), float32(h)}
+	glyph.TextureRect = Rect{float32(x), float32(y), float32(w), float32(h)}
+	return glyph
+}
+
+// findRect reserves space for a glyph in the texture, growing it if it's full
+func (p *fontPage) findRect(w, h int) (int, int) {
+	// Find the row that fits the glyph best
+	var row *fontRow
+	bestRatio := float32(0)
+	for i := range p.rows {
+		r := &p.rows[i]
+		ratio := float32(h) / float32(r.height)
+
+		// Ignore rows that are too small or too high
+		if ratio < 0.7 || ratio > 1 {
+			continue
+		}
+
+		// Check if there's enough horizontal space left in the row
+		if w > p.image.Rect.Dx()-r.width {
+			continue
+		}
+
+		if ratio > bestRatio {
+			row = r
+			bestRatio = ratio
+		}
+	}
+
+	// No suitable row, create a new one
+	if row == nil {
+		rowHeight := h + h/10
+		for p.nextRow+rowHeight > p.image.Rect.Dy() || w > p.image.Rect.Dx() {
+			p.grow()
+		}
+		p.rows = append(p.rows, fontRow{top: p.nextRow, height: rowHeight})
+		p.nextRow += rowHeight
+		row = &p.rows[len(p.rows)-1]
+	}
+
+	x, y := row.width, row.top
+	row.width += w
+	return x, y
+}
+
+// grow doubles the size of the texture, keeping the glyphs where they are
+func (p *fontPage) grow() {
+	size := p.image.Rect.Size().Mul(2)
+	img := image.NewNRGBA(image.Rectangle{Max: size})
+	draw.Draw(img, p.image.Rect, p.image, image.Point{}, draw.Src)
+	p.image = img
+	p.texture.setPixels(img)
+}
+
+func fixedToFloat(x fixed.Int26_6) float32 {
+	return float32(x) / 64
+}
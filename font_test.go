@@ -0,0 +1,78 @@
+package sf
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestFontGlyphs(t *testing.T) {
+	f, err := NewFontFromMemory(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Info().Family != "Go" {
+		t.Errorf("family: %q", f.Info().Family)
+	}
+	if f.LineSpacing(20) <= 20 || f.LineSpacing(40) <= f.LineSpacing(20) {
+		t.Errorf("line spacing: %v, %v", f.LineSpacing(20), f.LineSpacing(40))
+	}
+
+	a := f.Glyph('A', 20, false)
+	if a.Advance <= 0 || a.Bounds.H <= 0 || a.Bounds.Top >= 0 || a.Bounds.Top+a.Bounds.H > 1 {
+		t.Errorf("glyph A: %+v", a)
+	}
+	if f.Glyph('A', 20, false) != a {
+		t.Error("glyph not cached")
+	}
+	if bold := f.Glyph('A', 20, true); bold.Advance <= a.Advance || bold.Bounds.W <= a.Bounds.W {
+		t.Errorf("bold glyph A: %+v", bold)
+	}
+	if space := f.Glyph(' ', 20, false); space.Advance <= 0 || space.Bounds != (Rect{}) {
+		t.Errorf("space: %+v", space)
+	}
+
+	// Fill the texture until it grows, the first glyph must stay in place
+	tex := f.Texture(20)
+	for r := rune(0x21); r < 0x17f; r++ {
+		f.Glyph(r, 20, false)
+	}
+	if tex.Size().X <= fontPageSize {
+		t.Errorf("texture didn't grow: %v", tex.Size())
+	}
+	if !hasCoverage(tex.pixels, a.TextureRect) {
+		t.Error("glyph A lost when the texture grew")
+	}
+	if f.Texture(30) == tex {
+		t.Error("character sizes share a texture")
+	}
+}
+
+func TestFontBitmap(t *testing.T) {
+	f := NewFontFromFace(basicfont.Face7x13)
+	a := f.Glyph('A', 50, false)
+	if a.Advance != 7 || a.Bounds.H <= 0 || a.Bounds.H > 13 {
+		t.Errorf("glyph A: %+v", a)
+	}
+	if f.LineSpacing(50) != 13 || f.Texture(50) != f.Texture(10) {
+		t.Error("bitmap font should have a single size")
+	}
+	if !hasCoverage(f.Texture(10).pixels, a.TextureRect) {
+		t.Error("glyph A not rasterized")
+	}
+}
+
+// hasCoverage tells if some pixel inside rect isn't transparent
+func hasCoverage(img *image.NRGBA, rect Rect) bool {
+	r := image.Rect(int(rect.Left), int(rect.Top), int(rect.Left+rect.W), int(rect.Top+rect.H))
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.NRGBAAt(x, y).A != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package sf
+
+import (
+	"unsafe"
+
+	"github.com/go-gl-legacy/gl"
+	"github.com/go-gl/glfw3/v3.1/glfw"
+)
+
+// Fixed-function OpenGL implementation of Backend
+type glBackend struct {
+	window      *glfw.Window   // Window whose context we draw with, nil for the current context
+	framebuffer gl.Framebuffer // Framebuffer we draw into, 0 for the window's
+
+	lastVertices *Vertex // Vertices the vertex array pointers refer to, nil if not set
+}
+
+// NewGLBackend returns a Backend drawing with OpenGL into the framebuffer of
+// the current context. If no context is current when drawing starts, the
+// context shared by sf is created and activated; Init must have been called,
+// otherwise drawing fails (see RenderTarget.Err).
+func NewGLBackend() Backend {
+	return &glBackend{}
+}
+
+// The backend whose framebuffer is bound and whose states are set, nil if
+// something else changed the OpenGL states since
+var activeGLBackend *glBackend
+
+// invalidateGLStates forces the next backend activated to set all its states
+// again, after OpenGL was used outside of the render targets
+func invalidateGLStates() {
+	activeGLBackend = nil
+}
+
+func (b *glBackend) Activate() (bool, error) {
+	// Make sure there's an OpenGL context to draw with, checking first that
+	// the windowing system is still there
+	if err := ensureGlContext(); err != nil {
+		return false, err
+	}
+	if b.window != nil && glfw.GetCurrentContext() != b.window {
+		b.window.MakeContextCurrent()
+		activeGLBackend = nil
+	}
+
+	// Now's a good time to delete the garbage collected textures and shaders
+	deleteFinalizedTextures()
+	deleteFinalizedShaders()
+
+	if activeGLBackend == b {
+		return false, nil
+	}
+	b.framebuffer.Bind()
+	activeGLBackend = b
+	return true, nil
+}
+
+func (b *glBackend) Clear(color Color) {
+	gl.ClearColor(gl.GLclampf(float32(color.R)/255), gl.GLclampf(float32(color.G)/255),
+		gl.GLclampf(float32(color.B)/255), gl.GLclampf(float32(color.A)/255))
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+}
+
+func (b *glBackend) ClearStencil() {
+	gl.ClearStencil(0)
+	gl.Clear(gl.STENCIL_BUFFER_BIT)
+}
+
+func (b *glBackend) ResetStates() {
+	// Define the default OpenGL states
+	gl.Disable(gl.CULL_FACE)
+	gl.Disable(gl.LIGHTING)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.Disable(gl.ALPHA_TEST)
+	gl.Enable(gl.TEXTURE_2D)
+	gl.Enable(gl.BLEND)
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.EnableClientState(gl.VERTEX_ARRAY)
+	gl.EnableClientState(gl.COLOR_ARRAY)
+	gl.EnableClientState(gl.TEXTURE_COORD_ARRAY)
+
+	b.lastVertices = nil
+}
+
+func (b *glBackend) PushStates() {
+	gl.PushClientAttrib(gl.CLIENT_ALL_ATTRIB_BITS)
+	gl.PushAttrib(gl.ALL_ATTRIB_BITS)
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.PushMatrix()
+	gl.MatrixMode(gl.PROJECTION)
+	gl.PushMatrix()
+	gl.MatrixMode(gl.TEXTURE)
+	gl.PushMatrix()
+}
+
+func (b *glBackend) PopStates() {
+	gl.MatrixMode(gl.PROJECTION)
+	gl.PopMatrix()
+	gl.MatrixMode(gl.MODELVIEW)
+	gl.PopMatrix()
+	gl.MatrixMode(gl.TEXTURE)
+	gl.PopMatrix()
+	gl.PopClientAttrib()
+	gl.PopAttrib()
+}
+
+func (b *glBackend) SetViewport(x, y, w, h int) {
+	gl.Viewport(x, y, w, h)
+}
+
+func (b *glBackend) SetScissor(enabled bool, x, y, w, h int) {
+	if enabled {
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Scissor(x, y, w, h)
+	} else {
+		gl.Disable(gl.SCISSOR_TEST)
+	}
+}
+
+func (b *glBackend) SetStencilMode(mode StencilMode) {
+	if mode == StencilNone {
+		gl.Disable(gl.STENCIL_TEST)
+		gl.Disable(gl.ALPHA_TEST)
+		gl.ColorMask(true, true, true, true)
+		return
+	}
+
+	gl.Enable(gl.STENCIL_TEST)
+	switch mode {
+	case StencilWrite:
+		// Set the mask where the fragments aren't fully transparent, and
+		// leave the colors untouched
+		gl.StencilFunc(gl.ALWAYS, 1, 1)
+		gl.StencilOp(gl.KEEP, gl.KEEP, gl.REPLACE)
+		gl.Enable(gl.ALPHA_TEST)
+		gl.AlphaFunc(gl.GREATER, 0)
+		gl.ColorMask(false, false, false, false)
+	case StencilInside, StencilOutside:
+		if mode == StencilInside {
+			gl.StencilFunc(gl.EQUAL, 1, 1)
+		} else {
+			gl.StencilFunc(gl.NOTEQUAL, 1, 1)
+		}
+		gl.StencilOp(gl.KEEP, gl.KEEP, gl.KEEP)
+		gl.Disable(gl.ALPHA_TEST)
+		gl.ColorMask(true, true, true, true)
+	}
+}
+
+func (b *glBackend) SetProjection(transform Transform) {
+	gl.MatrixMode(gl.PROJECTION)
+	gl.LoadMatrixf(&transform.Matrix)
+
+	// Go back to model-view mode
+	gl.MatrixMode(gl.MODELVIEW)
+}
+
+func (b *glBackend) SetModelView(transform Transform) {
+	// No need to call glMatrixMode(gl.MODELVIEW), it is always the
+	// current mode (for optimization purpose, since it's the most used)
+	gl.LoadMatrixf(&transform.Matrix)
+}
+
+func (b *glBackend) SetBlendMode(mode BlendMode) {
+	// The separate functions are only used when needed, they require more
+	// recent OpenGL versions
+	if mode.ColorSrcFactor == mode.AlphaSrcFactor && mode.ColorDstFactor == mode.AlphaDstFactor {
+		gl.BlendFunc(glBlendFactors[mode.ColorSrcFactor], glBlendFactors[mode.ColorDstFactor])
+	} else {
+		gl.BlendFuncSeparate(glBlendFactors[mode.ColorSrcFactor], glBlendFactors[mode.ColorDstFactor],
+			glBlendFactors[mode.AlphaSrcFactor], glBlendFactors[mode.AlphaDstFactor])
+	}
+
+	if mode.ColorEquation == mode.AlphaEquation {
+		gl.BlendEquation(glBlendEquations[mode.ColorEquation])
+	} else {
+		gl.BlendEquationSeparate(glBlendEquations[mode.ColorEquation], glBlendEquations[mode.AlphaEquation])
+	}
+}
+
+// OpenGL values of the BlendFactor constants
+var glBlendFactors = [...]gl.GLenum{
+	FactorZero:             gl.ZERO,
+	FactorOne:              gl.ONE,
+	FactorSrcColor:         gl.SRC_COLOR,
+	FactorOneMinusSrcColor: gl.ONE_MINUS_SRC_COLOR,
+	FactorDstColor:         gl.DST_COLOR,
+	FactorOneMinusDstColor: gl.ONE_MINUS_DST_COLOR,
+	FactorSrcAlpha:         gl.SRC_ALPHA,
+	FactorOneMinusSrcAlpha: gl.ONE_MINUS_SRC_ALPHA,
+	FactorDstAlpha:         gl.DST_ALPHA,
+	FactorOneMinusDstAlpha: gl.ONE_MINUS_DST_ALPHA,
+}
+
+// OpenGL values of the BlendEquation constants
+var glBlendEquations = [...]gl.GLenum{
+	EquationAdd:             gl.FUNC_ADD,
+	EquationSubtract:        gl.FUNC_SUBTRACT,
+	EquationReverseSubtract: gl.FUNC_REVERSE_SUBTRACT,
+	EquationMin:             gl.MIN,
+	EquationMax:             gl.MAX,
+}
+
+func (b *glBackend) BindTexture(texture *Texture) {
+	texture.Bind(CoordPixels)
+}
+
+func (b *glBackend) BindShader(shader *Shader) {
+	if shader != nil {
+		shader.bind()
+	} else {
+		gl.Program(0).Use()
+	}
+}
+
+// OpenGL primitive types, indexed by PrimitiveType
+var glPrimitiveTypes = [...]gl.GLenum{gl.POINTS, gl.LINES, gl.LINE_STRIP, gl.TRIANGLES,
+	gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN, gl.QUADS}
+
+func (b *glBackend) DrawPrimitives(verts []Vertex, primType PrimitiveType) {
+	// Setup the pointers to the vertices' components, straight into the slice
+	// since Vertex is laid out the way OpenGL expects it
+	// ... and if they already point there, we don't need to set them again
+	if b.lastVertices != &verts[0] {
+		setVertexPointers(&verts[0].Pos, &verts[0].Color, &verts[0].TexCoords)
+		b.lastVertices = &verts[0]
+	}
+
+	// Draw the primitives
+	gl.DrawArrays(glPrimitiveTypes[primType], 0, len(verts))
+}
+
+func (b *glBackend) DrawBuffer(vb *VertexBuffer, first, count int, primType PrimitiveType) {
+	vb.upload()
+	vb.buffer.Bind(gl.ARRAY_BUFFER)
+
+	// Setup the pointers as offsets into the buffer
+	var v Vertex
+	setVertexPointers(unsafe.Offsetof(v.Pos), unsafe.Offsetof(v.Color), unsafe.Offsetof(v.TexCoords))
+
+	gl.DrawArrays(glPrimitiveTypes[primType], first, count)
+
+	// The pointers must be set again for the next client-side vertices
+	vb.buffer.Unbind(gl.ARRAY_BUFFER)
+	b.lastVertices = nil
+}
+
+// setVertexPointers points the vertex arrays to interleaved Vertex values,
+// given the address (or offset in the bound buffer) of each component of the
+// first vertex
+func setVertexPointers(pos, color, texCoords interface{}) {
+	stride := int(unsafe.Sizeof(Vertex{}))
+	gl.VertexPointer(2, gl.FLOAT, stride, pos)
+	gl.ColorPointer(4, gl.UNSIGNED_BYTE, stride, color)
+	gl.TexCoordPointer(2, gl.FLOAT, stride, texCoords)
+}
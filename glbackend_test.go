@@ -0,0 +1,103 @@
+package sf
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/go-gl-legacy/gl"
+)
+
+func TestVertexLayout(t *testing.T) {
+	// The OpenGL backend hands []Vertex to the vertex arrays as-is
+	var v Vertex
+	if unsafe.Offsetof(v.Pos) != 0 || unsafe.Offsetof(v.Color) != 8 ||
+		unsafe.Offsetof(v.TexCoords) != 12 || unsafe.Sizeof(v) != 20 {
+		t.Fail()
+	}
+}
+
+// newGLTarget returns a target drawing with OpenGL, or skips the test if
+// there's no display to create a context on
+func newGLTarget(tb testing.TB) *RenderTarget {
+	if err := Init(); err != nil {
+		tb.Skip(err)
+	}
+	if err := ensureGlContext(); err != nil {
+		Terminate()
+		tb.Skip(err)
+	}
+	tb.Cleanup(Terminate)
+
+	return NewRenderTarget(Vector2{800, 600})
+}
+
+// benchmarkQuads returns n quads filling an 800x600 target
+func benchmarkQuads(n int) []Vertex {
+	verts := make([]Vertex, 0, n*4)
+	for i := 0; i < n; i++ {
+		x, y := float32(i%80)*10, float32(i/80%60)*10
+		c := Color{uint8(i), uint8(i >> 8), 255, 255}
+		verts = append(verts,
+			Vertex{Vector2{x, y}, c, Vector2{}},
+			Vertex{Vector2{x, y + 10}, c, Vector2{}},
+			Vertex{Vector2{x + 10, y + 10}, c, Vector2{}},
+			Vertex{Vector2{x + 10, y}, c, Vector2{}})
+	}
+	return verts
+}
+
+// drawImmediate is how large batches were drawn before client vertex arrays,
+// kept for comparison
+func drawImmediate(verts []Vertex, primType PrimitiveType) {
+	gl.Begin(glPrimitiveTypes[primType])
+
+	for i := range verts {
+		gl.TexCoord2f(verts[i].TexCoords.X, verts[i].TexCoords.Y)
+		gl.Color4f(float32(verts[i].Color.R)/255, float32(verts[i].Color.G)/255,
+			float32(verts[i].Color.B)/255, float32(verts[i].Color.A)/255)
+		gl.Vertex2f(verts[i].Pos.X, verts[i].Pos.Y)
+	}
+
+	gl.End()
+}
+
+func benchmarkDraw(b *testing.B, quads int, immediate bool) {
+	target := newGLTarget(b)
+	verts := benchmarkQuads(quads)
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+
+	// Set the states up once
+	target.Render(verts, Quads, states)
+	gl.Finish()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if immediate {
+			drawImmediate(verts, Quads)
+		} else {
+			target.Render(verts, Quads, states)
+		}
+		gl.Finish()
+	}
+	b.ReportMetric(float64(len(verts)*b.N)/b.Elapsed().Seconds(), "vertices/s")
+}
+
+func BenchmarkDrawImmediate100(b *testing.B)      { benchmarkDraw(b, 100, true) }
+func BenchmarkDrawImmediate10000(b *testing.B)    { benchmarkDraw(b, 10000, true) }
+func BenchmarkDrawVertexArrays100(b *testing.B)   { benchmarkDraw(b, 100, false) }
+func BenchmarkDrawVertexArrays10000(b *testing.B) { benchmarkDraw(b, 10000, false) }
+
+func TestGLBackendWithoutContext(t *testing.T) {
+	if glfwInitialized {
+		t.Skip("OpenGL is available")
+	}
+
+	// The commands fail without panicking, the error is kept for Err
+	target := NewRenderTarget(Vector2{8, 8})
+	target.Clear(Color{0, 0, 0, 255})
+	target.Render(quad(0, 0, 8, 8, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+	target.ResetGLStates()
+	if target.Err() == nil || target.Stats().DrawCalls != 0 {
+		t.Errorf("error %v, stats %+v", target.Err(), target.Stats())
+	}
+}
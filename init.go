@@ -1,13 +1,101 @@
 package sf
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/go-gl-legacy/gl"
 	"github.com/go-gl/glfw3/v3.1/glfw"
 )
 
-func init() {
+var (
+	glfwInitialized bool         // Has Init been called successfully?
+	glInitialized   bool         // Have the OpenGL entry points been loaded?
+	contextWindow   *glfw.Window // Hidden window owning the shared OpenGL context
+)
+
+// Init initializes the windowing system. It must be called from the main
+// thread before creating windows or drawing with OpenGL, and be paired with a
+// call to Terminate.
+//
+// Nothing else in sf needs it: the math types (Vector2, Rect, Transform, ...)
+// and the software backend work without any windowing system.
+func Init() error {
+	if glfwInitialized {
+		return nil
+	}
 	if err := glfw.Init(); err != nil {
-		panic("Can't init glfw!")
+		return fmt.Errorf("sf: can't initialize glfw: %v", err)
+	}
+	glfwInitialized = true
+	return nil
+}
+
+// Terminate closes the windows still open, destroys the OpenGL context created
+// by sf and shuts down the windowing system. Drawing with OpenGL afterwards
+// fails, see RenderTarget.Err.
+func Terminate() {
+	if !glfwInitialized {
+		return
+	}
+	for w := range openWindows {
+		w.Close()
+	}
+	if contextWindow != nil {
+		contextWindow.Destroy()
+		contextWindow = nil
+	}
+	glfw.Terminate()
+	glfwInitialized = false
+	glInitialized = false
+
+	// The textures and shaders died with the contexts
+	texturesToDelete.Lock()
+	texturesToDelete.names = nil
+	texturesToDelete.Unlock()
+	shadersToDelete.Lock()
+	shadersToDelete.programs = nil
+	shadersToDelete.Unlock()
+}
+
+// sharedContext returns the hidden window whose OpenGL context is shared with
+// every window, creating it on first use.
+func sharedContext() (*glfw.Window, error) {
+	if !glfwInitialized {
+		return nil, errors.New("sf: Init must be called before using OpenGL")
+	}
+	if contextWindow == nil {
+		glfw.WindowHint(glfw.Visible, glfw.False)
+		glfw.WindowHint(glfw.StencilBits, 8)
+		w, err := glfw.CreateWindow(1, 1, "", nil, nil)
+		glfw.DefaultWindowHints()
+		if err != nil {
+			return nil, fmt.Errorf("sf: can't create an OpenGL context: %v", err)
+		}
+		contextWindow = w
+	}
+	return contextWindow, nil
+}
+
+// ensureGlContext makes sure an OpenGL context is current, activating the
+// shared one if there's none, and that the OpenGL entry points are loaded.
+func ensureGlContext() error {
+	if !glfwInitialized {
+		return errors.New("sf: Init must be called before using OpenGL")
+	}
+	if glfw.GetCurrentContext() == nil {
+		w, err := sharedContext()
+		if err != nil {
+			return err
+		}
+		w.MakeContextCurrent()
+	}
+
+	if !glInitialized {
+		if gl.Init() != 0 {
+			return errors.New("sf: can't load the OpenGL entry points")
+		}
+		glInitialized = true
 	}
-	gl.Init()
+	return nil
 }
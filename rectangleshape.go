@@ -0,0 +1,23 @@
+package sf
+
+// RectangleShape is a Shape with the geometry of a rectangle
+type RectangleShape struct {
+	Shape
+	size Vector2
+}
+
+func NewRectangleShape(size Vector2) *RectangleShape {
+	r := &RectangleShape{}
+	r.init()
+	r.SetSize(size)
+	return r
+}
+
+func (r *RectangleShape) SetSize(size Vector2) {
+	r.size = size
+	r.update([]Vector2{{0, 0}, {size.X, 0}, {size.X, size.Y}, {0, size.Y}})
+}
+
+func (r *RectangleShape) Size() Vector2 {
+	return r.size
+}
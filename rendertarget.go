@@ -1,20 +1,7 @@
 package sf
 
-import (
-	"github.com/go-gl-legacy/gl"
-)
-
 const vertexCacheSize = 4
 
-type BlendMode uint8
-
-const (
-	BlendAlpha    BlendMode = iota // Pixel = Source * Source.a + Dest * (1 - Source.a)
-	BlendAdd                       // Pixel = Source + Dest
-	BlendMultiply                  // Pixel = Source * Dest
-	BlendNone                      /// Pixel = Source
-)
-
 // Omg badass render times
 type PrimitiveType byte
 
@@ -28,34 +15,77 @@ const (
 	Quads
 )
 
+// How a RenderTarget adapts its views when its size changes
+type ResizePolicy uint8
+
+const (
+	ResizeStretch   ResizePolicy = iota // Views are kept, the scene is stretched to fill the target
+	ResizeLetterbox                     // Views are kept, drawn as large as possible without distorting them
+	ResizeExpand                        // Views grow with the target, showing more of the scene at the same scale
+)
+
+// RenderStates are the states used to draw. The zero BlendMode is drawn as
+// BlendAlpha.
 type RenderStates struct {
-	BlendMode BlendMode // Blending mode
-	Transform Transform // Transform
-	Texture   *Texture  // Textures
-	//Shader *Shader // Shader
+	BlendMode BlendMode   // Blending mode, BlendAlpha if zero
+	Transform Transform   // Transform
+	Texture   *Texture    // Textures
+	Shader    *Shader     // Shader
+	Clip      *Clip       // Clip rectangle, combined with the target's clip stack
+	Stencil   StencilMode // Use of the stencil mask, the target's mask mode if StencilNone
+}
+
+// Statistics about the drawing done by a RenderTarget
+type RenderStats struct {
+	DrawCalls    int // Number of draws issued to the backend
+	Vertices     int // Number of vertices drawn
+	Batches      int // Number of batches drawn, each being one draw call
+	BatchedDraws int // Number of Render calls merged into batches
 }
 
 type RenderTarget struct {
 	size Vector2
 
-	view        *View
-	defaultView *View
+	view         *View
+	defaultView  *View
+	resizePolicy ResizePolicy // How the views adapt to size changes
+	clips        []Clip       // Clip rectangles pushed
+	maskMode     StencilMode  // Use of the stencil mask by the draws not setting one
 
-	// Cache
-	glStatesSet    bool      // Are our internal GL states set yet?
-	viewChanged    bool      // Has the current view changed since last draw?
-	lastBlendMode  BlendMode // Cached blending mode
-	lastTextureId  uint64    // Cached texture
-	useVertexCache bool      // Did we previously use the vertex cache?
-	//vertexCache    [vertexCacheSize]Vertex // Pre-transformed vertices cache
+	backend Backend // Does the actual drawing
 
-	vpCache [vertexCacheSize]Vector2
-	vcCache [vertexCacheSize]Color
-	vtCache [vertexCacheSize]Vector2
+	// Cache
+	glStatesSet     bool                    // Are our internal GL states set yet?
+	viewChanged     bool                    // Has the current view changed since last draw?
+	lastBlendMode   BlendMode               // Cached blending mode
+	lastTextureId   uint64                  // Cached texture
+	lastScissor     scissorBox              // Cached scissor box
+	lastStencilMode StencilMode             // Cached use of the stencil mask
+	useVertexCache  bool                    // Did we previously use the vertex cache?
+	vertexCache     [vertexCacheSize]Vertex // Pre-transformed vertices cache
+
+	// Batching
+	batching    bool          // Are consecutive draws with the same states merged?
+	batch       []Vertex      // Pre-transformed vertices waiting to be drawn
+	batchType   PrimitiveType // Primitive type of the pending batch
+	batchStates RenderStates  // States of the pending batch
+
+	targetTexture *Texture // Texture drawn into, for render textures
+
+	stats RenderStats
+	err   error // First error that made a command fail
 }
 
+// NewRenderTarget creates a render target drawing with OpenGL into the
+// framebuffer of the current context.
 func NewRenderTarget(size Vector2) *RenderTarget {
-	rt := &RenderTarget{size: size}
+	return NewRenderTargetWithBackend(size, NewGLBackend())
+}
+
+// NewRenderTargetWithBackend creates a render target that draws through the
+// given backend.
+func NewRenderTargetWithBackend(size Vector2, backend Backend) *RenderTarget {
+	rt := &RenderTarget{size: size, backend: backend}
 	rt.glStatesSet = false
 	rt.defaultView = NewView()
 	rt.defaultView.Reset(Rect{0, 0, rt.size.X, rt.size.Y})
@@ -65,19 +95,61 @@ func NewRenderTarget(size Vector2) *RenderTarget {
 }
 
 func (r *RenderTarget) Clear(color Color) {
-	gl.ClearColor(gl.GLclampf(float32(color.R)/255), gl.GLclampf(float32(color.G)/255),
-		gl.GLclampf(float32(color.B)/255), gl.GLclampf(float32(color.A)/255))
-	gl.Clear(gl.COLOR_BUFFER_BIT)
+	r.Flush()
+	if r.activate() != nil {
+		return
+	}
+	r.prepareClear()
+	r.backend.Clear(color)
+}
+
+// prepareClear disables the states restricting what clearing writes: the
+// scissor test and the color mask of the stencil writes
+func (r *RenderTarget) prepareClear() {
+	if !r.glStatesSet || r.lastScissor.enabled {
+		r.applyClip(nil)
+	}
+	if !r.glStatesSet || r.lastStencilMode == StencilWrite {
+		r.applyStencilMode(StencilNone)
+	}
 }
 
 func (r *RenderTarget) SetView(view View) {
+	r.Flush()
 	*(r.view) = view
 	r.viewChanged = true
 }
 
+// SetSize tells the target its new size, in pixels. RenderWindow calls it
+// when its framebuffer is resized; the views are updated according to the
+// resize policy.
 func (r *RenderTarget) SetSize(size Vector2) {
+	r.Flush()
+	if r.resizePolicy == ResizeExpand && r.size.X > 0 && r.size.Y > 0 {
+		if r.view.equals(r.defaultView) {
+			r.defaultView.Reset(Rect{0, 0, size.X, size.Y})
+			*(r.view) = *(r.defaultView)
+		} else {
+			r.defaultView.Reset(Rect{0, 0, size.X, size.Y})
+			viewSize := r.view.Size()
+			r.view.SetSizeXY(viewSize.X*size.X/r.size.X, viewSize.Y*size.Y/r.size.Y)
+		}
+	}
+
 	r.size = size
-	r.defaultView.Reset(Rect{0, 0, r.size.X, r.size.Y})
+	r.viewChanged = true
+}
+
+// SetResizePolicy sets how the views adapt when the size of the target
+// changes. The default is ResizeStretch.
+func (r *RenderTarget) SetResizePolicy(policy ResizePolicy) {
+	r.Flush()
+	r.resizePolicy = policy
+	r.viewChanged = true
+}
+
+func (r *RenderTarget) ResizePolicy() ResizePolicy {
+	return r.resizePolicy
 }
 
 func (r *RenderTarget) Size() Vector2 {
@@ -96,11 +168,85 @@ func (r *RenderTarget) Viewport(view *View) Rect {
 	w := r.size.X
 	h := r.size.Y
 	viewport := view.Viewport()
+	rect := Rect{w * viewport.Left, h * viewport.Top, w * viewport.W, h * viewport.H}
+
+	// Shrink the viewport to the aspect ratio of the view, and center it
+	if r.resizePolicy == ResizeLetterbox && view.size.X > 0 && view.size.Y > 0 && rect.H > 0 {
+		aspect := view.size.X / view.size.Y
+		if rect.W/rect.H > aspect {
+			width := rect.H * aspect
+			rect.Left += (rect.W - width) / 2
+			rect.W = width
+		} else {
+			height := rect.W / aspect
+			rect.Top += (rect.H - height) / 2
+			rect.H = height
+		}
+	}
+
+	return Rect{0.5 + rect.Left, 0.5 + rect.Top, rect.W, rect.H}
+}
+
+// SetBatching enables or disables batching. When enabled, consecutive Render
+// calls drawing Points, Lines, Triangles or Quads with the same primitive type,
+// texture, blend mode, shader, clip and stencil mode are transformed on the CPU
+// and merged into a single draw. The batch is drawn when the states change, on
+// Clear, ClearMask, Display and view changes, or by calling Flush.
+//
+// The batch is also drawn before its texture changes (Update, SetSmooth,
+// drawing into the RenderTexture owning it...), so that the texture is drawn
+// as it was when Render was called.
+//
+// Since the shader of a batch is only applied when it's drawn, call Flush
+// before changing the parameters of a shader used by the pending draws.
+func (r *RenderTarget) SetBatching(enabled bool) {
+	if !enabled {
+		r.Flush()
+	}
+	r.batching = enabled
+}
+
+func (r *RenderTarget) IsBatching() bool {
+	return r.batching
+}
+
+// Flush draws the pending batch, if any
+func (r *RenderTarget) Flush() {
+	if len(r.batch) == 0 {
+		return
+	}
+
+	// Empty the batch first, drawing may flush again (e.g. when setting the view)
+	verts := r.batch
+	r.batch = r.batch[:0]
+	for i, target := range pendingBatches {
+		if target == r {
+			pendingBatches = append(pendingBatches[:i], pendingBatches[i+1:]...)
+			break
+		}
+	}
+
+	// The vertices are already transformed
+	states := r.batchStates
+	states.Transform = IdentityTransform()
+	r.draw(verts, r.batchType, states)
+	r.stats.Batches++
+}
+
+// Stats returns the statistics gathered since the last call to ResetStats
+func (r *RenderTarget) Stats() RenderStats {
+	return r.stats
+}
 
-	return Rect{0.5 + w*viewport.Left,
-		0.5 + h*viewport.Top,
-		w * viewport.W,
-		h * viewport.H}
+func (r *RenderTarget) ResetStats() {
+	r.stats = RenderStats{}
+}
+
+// Err returns the first error that made a command of the target fail, e.g.
+// drawing with OpenGL before Init was called or after Terminate. The commands
+// failing are skipped, they don't report errors themselves.
+func (r *RenderTarget) Err() error {
+	return r.err
 }
 
 func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states RenderStates) {
@@ -108,6 +254,43 @@ func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states Ren
 	if len(verts) == 0 {
 		return
 	}
+	states.BlendMode = states.BlendMode.orDefault()
+	states.Clip = r.resolveClip(states.Clip)
+	states.Stencil = r.resolveStencil(states.Stencil)
+
+	// Strips and fans can't be merged with other draws
+	if r.batching && (primType == Points || primType == Lines || primType == Triangles ||
+		primType == Quads) {
+		if len(r.batch) > 0 && (primType != r.batchType || states.Texture != r.batchStates.Texture ||
+			states.BlendMode != r.batchStates.BlendMode || states.Shader != r.batchStates.Shader ||
+			!sameClip(states.Clip, r.batchStates.Clip) || states.Stencil != r.batchStates.Stencil) {
+			r.Flush()
+		}
+
+		// Pre-transform the vertices and add them to the batch
+		if len(r.batch) == 0 {
+			pendingBatches = append(pendingBatches, r)
+		}
+		for i := range verts {
+			v := verts[i]
+			v.Pos = states.Transform.TransformPoint(v.Pos)
+			r.batch = append(r.batch, v)
+		}
+		r.batchType = primType
+		r.batchStates = states
+		r.stats.BatchedDraws++
+		return
+	}
+
+	r.Flush()
+	r.draw(verts, primType, states)
+}
+
+// draw draws vertices right away
+func (r *RenderTarget) draw(verts []Vertex, primType PrimitiveType, states RenderStates) {
+	if r.activate() != nil {
+		return
+	}
 
 	// First set the persistent OpenGL states if it's the very first call
 	if !r.glStatesSet {
@@ -119,9 +302,9 @@ func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states Ren
 	if useVertexCache {
 		// Pre-transform the vertices and store them into the vertex cache
 		for i := 0; i < len(verts); i++ {
-			r.vpCache[i] = states.Transform.TransformPoint(verts[i].Pos)
-			r.vcCache[i] = verts[i].Color
-			r.vtCache[i] = verts[i].TexCoords
+			r.vertexCache[i].Pos = states.Transform.TransformPoint(verts[i].Pos)
+			r.vertexCache[i].Color = verts[i].Color
+			r.vertexCache[i].TexCoords = verts[i].TexCoords
 		}
 
 		// Since vertices are transformed, we must use an identity transform to render them
@@ -132,6 +315,71 @@ func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states Ren
 		r.applyTransform(states.Transform)
 	}
 
+	r.applyStates(states)
+
+	// Draw the primitives, from the cache if we pre-transformed them
+	if useVertexCache {
+		r.backend.DrawPrimitives(r.vertexCache[:len(verts)], primType)
+	} else {
+		r.backend.DrawPrimitives(verts, primType)
+	}
+
+	// Unbind the shader, if any
+	if states.Shader != nil {
+		r.applyShader(nil)
+	}
+
+	// Update the cache
+	r.useVertexCache = useVertexCache
+
+	r.stats.DrawCalls++
+	r.stats.Vertices += len(verts)
+}
+
+// RenderBuffer draws count vertices of a vertex buffer, starting at first
+func (r *RenderTarget) RenderBuffer(vb *VertexBuffer, first, count int, primType PrimitiveType,
+	states RenderStates) {
+	// Clamp the range to the buffer, and check that there's something to draw
+	if first < 0 || first >= vb.VertexCount() || count <= 0 {
+		return
+	}
+	if first+count > vb.VertexCount() {
+		count = vb.VertexCount() - first
+	}
+	states.BlendMode = states.BlendMode.orDefault()
+	states.Clip = r.resolveClip(states.Clip)
+	states.Stencil = r.resolveStencil(states.Stencil)
+
+	r.Flush()
+	if r.activate() != nil {
+		return
+	}
+
+	// First set the persistent OpenGL states if it's the very first call
+	if !r.glStatesSet {
+		r.resetGlStates()
+	}
+
+	// The vertices are stored in the buffer, we can't pre-transform them
+	r.applyTransform(states.Transform)
+	r.applyStates(states)
+
+	r.backend.DrawBuffer(vb, first, count, primType)
+
+	// Unbind the shader, if any
+	if states.Shader != nil {
+		r.applyShader(nil)
+	}
+
+	// Update the cache
+	r.useVertexCache = false
+
+	r.stats.DrawCalls++
+	r.stats.Vertices += count
+}
+
+// applyStates applies the view and the states that changed since the last draw
+func (r *RenderTarget) applyStates(states RenderStates) {
 	// Apply the view
 	if r.viewChanged {
 		r.applyCurrentView()
@@ -142,6 +390,17 @@ func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states Ren
 		r.applyBlendMode(states.BlendMode)
 	}
 
+	// Apply the clip rectangle, whose scissor box changes with the size of the
+	// target
+	if r.scissorBox(states.Clip) != r.lastScissor {
+		r.applyClip(states.Clip)
+	}
+
+	// Apply the use of the stencil mask
+	if states.Stencil != r.lastStencilMode {
+		r.applyStencilMode(states.Stencil)
+	}
+
 	// Apply the texture
 	var textureId uint64
 	if states.Texture != nil {
@@ -152,106 +411,88 @@ func (r *RenderTarget) Render(verts []Vertex, primType PrimitiveType, states Ren
 	}
 
 	// Apply the shader
-	// TODO
-	/*if states.shader {
-		applyShader(states.shader);
-	}*/
-
-	// #########################################
-
-	if !useVertexCache {
-		// Find the OpenGL primitive type
-		modes := [...]gl.GLenum{gl.POINTS, gl.LINES, gl.LINE_STRIP, gl.TRIANGLES,
-			gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN, gl.QUADS}
-		mode := modes[primType]
-
-		gl.Begin(mode)
-
-		for i, _ := range verts {
-			gl.TexCoord2f(verts[i].TexCoords.X, verts[i].TexCoords.Y)
-			gl.Color4f(float32(verts[i].Color.R)/255, float32(verts[i].Color.G)/255,
-				float32(verts[i].Color.B)/255, float32(verts[i].Color.A)/255)
-			gl.Vertex2f(verts[i].Pos.X, verts[i].Pos.Y)
-		}
-
-		gl.End()
+	if states.Shader != nil {
+		r.applyShader(states.Shader)
 	}
+}
 
-	// #########################################
+// activate makes the target the destination of the drawing commands. If it
+// fails, the error is recorded for Err and the command must be skipped.
+func (r *RenderTarget) activate() error {
+	// The pending batches drawing our texture must be drawn before it changes
+	if r.targetTexture != nil {
+		flushBatches(r.targetTexture)
+	}
 
-	// Setup the pointers to the vertices' components
-	// ... and if we already used it previously, we don't need to set the pointers again
-	if useVertexCache {
-		if !r.useVertexCache {
-			gl.VertexPointer(2, gl.FLOAT, 0, r.vpCache[:])
-			gl.ColorPointer(4, gl.UNSIGNED_BYTE, 0, r.vcCache[:])
-			gl.TexCoordPointer(2, gl.FLOAT, 0, r.vtCache[:])
+	changed, err := r.backend.Activate()
+	if err != nil {
+		if r.err == nil {
+			r.err = err
 		}
+		return err
+	}
 
-		// Find the OpenGL primitive type
-		modes := [...]gl.GLenum{gl.POINTS, gl.LINES, gl.LINE_STRIP, gl.TRIANGLES,
-			gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN, gl.QUADS}
-		mode := modes[primType]
-
-		// Draw the primitives
-		gl.DrawArrays(mode, 0, len(verts))
+	// Another target changed the states, our cache is no longer valid
+	if changed {
+		r.glStatesSet = false
 	}
+	return nil
+}
 
-	// Unbind the shader, if any
-	// TODO
-	/*if (states.shader) {
-		r.applyShader(nil)
-	}*/
+// PushGLStates saves the OpenGL states and sets the ones sf expects, so that
+// sf can draw in the middle of code using OpenGL directly. It must be paired
+// with PopGLStates. Saving the states is costly: ResetGLStates is faster when
+// they don't need to be restored.
+func (r *RenderTarget) PushGLStates() {
+	r.Flush()
 
-	// Update the cache
-	r.useVertexCache = useVertexCache
+	// The states may have been changed outside of sf
+	invalidateGLStates()
+	if r.activate() != nil {
+		return
+	}
+	r.backend.PushStates()
+	r.resetGlStates()
 }
 
-func (r *RenderTarget) pushGlStates() {
-	gl.PushClientAttrib(gl.CLIENT_ALL_ATTRIB_BITS)
-	gl.PushAttrib(gl.ALL_ATTRIB_BITS)
-	gl.MatrixMode(gl.MODELVIEW)
-	gl.PushMatrix()
-	gl.MatrixMode(gl.PROJECTION)
-	gl.PushMatrix()
-	gl.MatrixMode(gl.TEXTURE)
-	gl.PushMatrix()
+// PopGLStates restores the OpenGL states saved by PushGLStates, so that code
+// using OpenGL directly can go on after sf drew.
+func (r *RenderTarget) PopGLStates() {
+	r.Flush()
+	if r.activate() != nil {
+		return
+	}
+	r.backend.PopStates()
 
-	r.resetGlStates()
+	// The restored states aren't the ones sf set, they must be set again
+	// before sf draws
+	r.glStatesSet = false
+	invalidateGLStates()
 }
 
-func (r *RenderTarget) popGlStates() {
-	gl.MatrixMode(gl.PROJECTION)
-	gl.PopMatrix()
-	gl.MatrixMode(gl.MODELVIEW)
-	gl.PopMatrix()
-	gl.MatrixMode(gl.TEXTURE)
-	gl.PopMatrix()
-	gl.PopClientAttrib()
-	gl.PopAttrib()
+// ResetGLStates sets the OpenGL states sf expects, after code using OpenGL
+// directly changed them. Unlike PushGLStates, the previous states are lost.
+func (r *RenderTarget) ResetGLStates() {
+	r.Flush()
+	invalidateGLStates()
+	if r.activate() != nil {
+		return
+	}
+	r.resetGlStates()
 }
 
 func (r *RenderTarget) resetGlStates() {
-	// Define the default OpenGL states
-	gl.Disable(gl.CULL_FACE)
-	gl.Disable(gl.LIGHTING)
-	gl.Disable(gl.DEPTH_TEST)
-	gl.Disable(gl.ALPHA_TEST)
-	gl.Enable(gl.TEXTURE_2D)
-	gl.Enable(gl.BLEND)
-	gl.MatrixMode(gl.MODELVIEW)
-	gl.EnableClientState(gl.VERTEX_ARRAY)
-	gl.EnableClientState(gl.COLOR_ARRAY)
-	gl.EnableClientState(gl.TEXTURE_COORD_ARRAY)
+	// Define the default backend states
+	r.backend.ResetStates()
 	r.glStatesSet = true
 
 	// Apply the default SFML states
-	r.applyBlendMode(BlendAlpha)
+	r.applyBlendMode(BlendAlpha())
 	r.applyTransform(IdentityTransform())
 	r.applyTexture(nil)
-	/*if (Shader::isAvailable()){
-		r.applyShader(nil)
-	}*/
+	r.applyShader(nil)
+	r.applyClip(nil)
+	r.applyStencilMode(StencilNone)
 	r.useVertexCache = false
 
 	// Set the default view
@@ -262,64 +503,30 @@ func (r *RenderTarget) applyCurrentView() {
 	// Set the viewport
 	viewport := r.Viewport(r.view)
 	top := r.size.Y - (viewport.Top + viewport.H)
-	gl.Viewport(int(viewport.Left), int(top), int(viewport.W), int(viewport.H))
-
-	mat := r.view.Transform().Matrix
+	r.backend.SetViewport(int(viewport.Left), int(top), int(viewport.W), int(viewport.H))
 
 	// Set the projection matrix
-	gl.MatrixMode(gl.PROJECTION)
-	gl.LoadMatrixf(&mat)
-
-	// Go back to model-view mode
-	gl.MatrixMode(gl.MODELVIEW)
+	r.backend.SetProjection(r.view.Transform())
 
 	r.viewChanged = false
 }
 
 func (r *RenderTarget) applyBlendMode(mode BlendMode) {
-	switch mode {
-	// glBlendFuncSeparateEXT is used when available to avoid an incorrect alpha value when the target
-	// is a RenderTexture -- in this case the alpha value must be written directly to the target buffer
-
-	// Alpha blending
-	default:
-		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-
-	case BlendAlpha:
-		/*if (GLEW_EXT_blend_func_separate) {
-		    glBlendFuncSeparateEXT(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA, gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
-		} else {*/
-		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-		//}
-
-	// Additive blending
-	case BlendAdd:
-		/*if GLEW_EXT_blend_func_separate {
-			gl.BlendFuncSeparateEXT(gl.SRC_ALPHA, gl.ONE, gl.ONE, gl.ONE)
-		} else {*/
-		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE)
-		//}
-
-	// Multiplicative blending
-	case BlendMultiply:
-		gl.BlendFunc(gl.DST_COLOR, gl.ZERO)
-
-	// No blending
-	case BlendNone:
-		gl.BlendFunc(gl.ONE, gl.ZERO)
-	}
-
+	r.backend.SetBlendMode(mode)
 	r.lastBlendMode = mode
 }
 
 func (r *RenderTarget) applyTransform(transform Transform) {
-	// No need to call glMatrixMode(gl.MODELVIEW), it is always the
-	// current mode (for optimization purpose, since it's the most used)
-	gl.LoadMatrixf(&transform.Matrix)
+	r.backend.SetModelView(transform)
 }
 
 func (r *RenderTarget) applyTexture(texture *Texture) {
-	texture.Bind(CoordPixels)
+	// Destroyed textures are drawn as no texture
+	if texture != nil && texture.destroyed {
+		texture = nil
+	}
+
+	r.backend.BindTexture(texture)
 
 	if texture != nil {
 		r.lastTextureId = texture.cacheId
@@ -328,6 +535,28 @@ func (r *RenderTarget) applyTexture(texture *Texture) {
 	}
 }
 
-/*func (r *RenderTarget) applyShader(shader *Shader) {
-	Shader::bind(shader);
-}*/
+func (r *RenderTarget) applyShader(shader *Shader) {
+	// Destroyed shaders are drawn as no shader
+	if shader != nil && shader.destroyed {
+		shader = nil
+	}
+
+	r.backend.BindShader(shader)
+}
+
+// Render targets with a pending batch
+var pendingBatches []*RenderTarget
+
+// flushBatches draws the pending batches using a texture, before it changes
+func flushBatches(texture *Texture) {
+	if len(pendingBatches) == 0 {
+		return
+	}
+
+	// Flushing removes the targets from the list
+	for _, r := range append([]*RenderTarget(nil), pendingBatches...) {
+		if r.batchStates.Texture == texture {
+			r.Flush()
+		}
+	}
+}
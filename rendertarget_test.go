@@ -0,0 +1,196 @@
+package sf
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestResizePolicies(t *testing.T) {
+	newTarget := func(policy ResizePolicy) *RenderTarget {
+		img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+		r := NewRenderTargetWithBackend(Vector2{400, 300}, NewSoftwareBackend(img))
+		r.SetResizePolicy(policy)
+		r.SetSize(Vector2{800, 300})
+		return r
+	}
+
+	// The view keeps showing the initial area, stretched over the whole target
+	r := newTarget(ResizeStretch)
+	if r.view.Size() != (Vector2{400, 300}) || r.Viewport(r.view) != (Rect{0.5, 0.5, 800, 300}) {
+		t.Errorf("stretch: view size %v, viewport %v", r.view.Size(), r.Viewport(r.view))
+	}
+
+	// The view keeps its aspect ratio, centered horizontally
+	r = newTarget(ResizeLetterbox)
+	if r.view.Size() != (Vector2{400, 300}) || r.Viewport(r.view) != (Rect{200.5, 0.5, 400, 300}) {
+		t.Errorf("letterbox: view size %v, viewport %v", r.view.Size(), r.Viewport(r.view))
+	}
+
+	// The default view grows with the target, and the current view follows it
+	r = newTarget(ResizeExpand)
+	if r.defaultView.Center() != (Vector2{400, 150}) || r.view.Size() != (Vector2{800, 300}) {
+		t.Errorf("expand: default view center %v, view size %v", r.defaultView.Center(), r.view.Size())
+	}
+
+	// A custom view is scaled by the same factor, around its center
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	r = NewRenderTargetWithBackend(Vector2{400, 300}, NewSoftwareBackend(img))
+	r.SetResizePolicy(ResizeExpand)
+	view := NewView()
+	view.Reset(Rect{100, 100, 200, 100})
+	r.SetView(*view)
+	r.SetSize(Vector2{800, 600})
+	if r.view.Center() != (Vector2{200, 150}) || r.view.Size() != (Vector2{400, 200}) {
+		t.Errorf("expand: custom view center %v, size %v", r.view.Center(), r.view.Size())
+	}
+
+	// Pending draws are drawn with the policy they were made with
+	img = image.NewRGBA(image.Rect(0, 0, 8, 4))
+	r = NewRenderTargetWithBackend(Vector2{8, 4}, NewSoftwareBackend(img))
+	r.SetBatching(true)
+	view.Reset(Rect{0, 0, 4, 4})
+	r.SetView(*view)
+	r.Render(quad(0, 0, 4, 4, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+	r.SetResizePolicy(ResizeLetterbox)
+	r.Flush()
+	if got := countPixels(img, color.RGBA{255, 255, 255, 255}); got != 32 {
+		t.Errorf("batch drawn after changing the policy: %d pixels", got)
+	}
+}
+
+func TestRenderBuffer(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	r := NewRenderTargetWithBackend(Vector2{8, 8}, NewSoftwareBackend(img))
+	r.Clear(Color{0, 0, 0, 255})
+
+	white := Color{255, 255, 255, 255}
+	vb := NewVertexBuffer(4, UsageStatic)
+	vb.Update(0, quad(0, 0, 2, 2, white))
+	if err := vb.Update(4, quad(4, 4, 2, 2, white)); err != nil || vb.VertexCount() != 8 {
+		t.Fatalf("update past the end: %v, %d vertices", err, vb.VertexCount())
+	}
+	if err := vb.Update(9, quad(0, 0, 1, 1, white)); err == nil {
+		t.Error("update leaving a gap should fail")
+	}
+
+	// Only the second quad, moved by the transform
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+	states.Transform.TranslateXY(1, 0)
+	r.RenderBuffer(vb, 4, 100, Quads, states)
+
+	if countPixels(img, color.RGBA{255, 255, 255, 255}) != 4 || img.RGBAAt(5, 4).R != 255 {
+		t.Fail()
+	}
+}
+
+func TestBatching(t *testing.T) {
+	drawScene := func(r *RenderTarget) {
+		r.Clear(Color{0, 0, 0, 255})
+		states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+		for i := 0; i < 5; i++ {
+			states.Transform = IdentityTransform()
+			states.Transform.TranslateXY(float32(i), float32(i))
+			r.Render(quad(0, 0, 3, 2, Color{255, 0, 0, 100}), Quads, states)
+		}
+
+		// A different blend mode breaks the batch
+		states.BlendMode = BlendAdd()
+		r.Render(quad(4, 0, 4, 4, Color{0, 0, 255, 255}), Quads, states)
+		r.Flush()
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	direct := NewRenderTargetWithBackend(Vector2{8, 8}, NewSoftwareBackend(img))
+	drawScene(direct)
+
+	batchedImg := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	batched := NewRenderTargetWithBackend(Vector2{8, 8}, NewSoftwareBackend(batchedImg))
+	batched.SetBatching(true)
+	drawScene(batched)
+
+	for i := range img.Pix {
+		if img.Pix[i] != batchedImg.Pix[i] {
+			t.Fatalf("batched rendering differs at byte %d", i)
+		}
+	}
+
+	if stats := direct.Stats(); stats != (RenderStats{6, 24, 0, 0}) {
+		t.Errorf("direct: %+v", stats)
+	}
+	if stats := batched.Stats(); stats != (RenderStats{2, 24, 2, 6}) {
+		t.Errorf("batched: %+v", stats)
+	}
+}
+
+func TestBatchingTextureChange(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, img := newSoftwareTarget(2, 1)
+	target.SetBatching(true)
+	target.Clear(Color{0, 0, 0, 255})
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex}
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+
+	// The pending batch is drawn with the texture as it was
+	src.SetNRGBA(0, 0, color.NRGBA{0, 255, 0, 255})
+	if err := tex.Update(src, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	target.Render(quad(1, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+	target.Flush()
+
+	if img.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || img.RGBAAt(1, 0) != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("pixels: %v, %v", img.RGBAAt(0, 0), img.RGBAAt(1, 0))
+	}
+	if len(pendingBatches) != 0 {
+		t.Errorf("%d pending batches left", len(pendingBatches))
+	}
+}
+
+func TestGLStates(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	backend := NewSoftwareBackend(img)
+	target := NewRenderTargetWithBackend(Vector2{1, 1}, backend)
+	white := quad(0, 0, 1, 1, Color{255, 255, 255, 128})
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+	black := Color{0, 0, 0, 255}
+
+	target.Clear(black)
+	target.Render(white, Quads, states)
+	want := img.RGBAAt(0, 0)
+
+	// Someone else changes the states behind the target's back
+	backend.SetBlendMode(BlendNone())
+	target.Clear(black)
+	target.ResetGLStates()
+	target.Render(white, Quads, states)
+	if got := img.RGBAAt(0, 0); got != want {
+		t.Errorf("after ResetGLStates: %v, want %v", got, want)
+	}
+
+	// Their states are restored after sf drew
+	backend.SetBlendMode(BlendMultiply())
+	target.Clear(black)
+	target.PushGLStates()
+	target.Render(white, Quads, states)
+	target.PopGLStates()
+	if got := img.RGBAAt(0, 0); got != want {
+		t.Errorf("between PushGLStates and PopGLStates: %v, want %v", got, want)
+	}
+	if mode := backend.(*softwareBackend).blendMode; mode != BlendMultiply() {
+		t.Errorf("blend mode %v not restored", mode)
+	}
+
+	// And sf sets its own again before drawing
+	target.Clear(black)
+	target.Render(white, Quads, states)
+	if got := img.RGBAAt(0, 0); got != want {
+		t.Errorf("after PopGLStates: %v, want %v", got, want)
+	}
+}
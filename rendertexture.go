@@ -0,0 +1,226 @@
+package sf
+
+import (
+	"errors"
+
+	"github.com/go-gl-legacy/gl"
+	"github.com/go-gl/glfw3/v3.1/glfw"
+)
+
+// RenderTexture is a RenderTarget drawing off-screen into a Texture, through
+// an OpenGL framebuffer object.
+type RenderTexture struct {
+	*RenderTarget
+
+	texture *Texture   // Texture receiving the drawing
+	backend *glBackend // Backend drawing into our framebuffer
+	samples int        // Number of samples per pixel, 0 if multisampling is disabled
+
+	renderTextureBuffers
+}
+
+// OpenGL objects of a render texture, besides the texture
+type renderTextureBuffers struct {
+	framebuffer   gl.Framebuffer  // Framebuffer the texture is attached to
+	msFramebuffer gl.Framebuffer  // Multisampled framebuffer drawn into, if multisampling is enabled
+	msColorbuffer gl.Renderbuffer // Color storage of the multisampled framebuffer
+	stencilbuffer gl.Renderbuffer // Stencil storage of the framebuffer drawn into
+}
+
+// NewRenderTexture creates a render texture of the given size in pixels.
+// If samples is greater than 0, drawing is multisampled with that many samples
+// per pixel (clamped to what the hardware supports) and resolved into the
+// texture by Display. Init must have been called.
+func NewRenderTexture(width, height, samples int) (*RenderTexture, error) {
+	if err := ensureGlContext(); err != nil {
+		return nil, err
+	}
+
+	// The framebuffer objects aren't shared between contexts, remember the one
+	// they're created in
+	t := &RenderTexture{texture: &Texture{}, samples: samples}
+	t.backend = &glBackend{window: glfw.GetCurrentContext()}
+	if err := t.create(width, height); err != nil {
+		return nil, err
+	}
+
+	t.RenderTarget = NewRenderTargetWithBackend(Vector2{float32(width), float32(height)}, t.backend)
+	t.targetTexture = t.texture
+	return t, nil
+}
+
+// Texture returns the texture the render texture draws into. It stays the
+// same when the render texture is resized.
+func (t *RenderTexture) Texture() *Texture {
+	return t.texture
+}
+
+// Resize changes the size of the render texture. Its content is lost. If it
+// fails, the render texture keeps its previous size and content.
+func (t *RenderTexture) Resize(width, height int) error {
+	if t.texture.destroyed {
+		return errors.New("sf: render texture destroyed")
+	}
+	t.Flush()
+	flushBatches(t.texture)
+
+	// Keep the current texture and framebuffers until the new ones are complete
+	oldBuffers, oldTexture, oldSamples := t.renderTextureBuffers, *t.texture, t.samples
+	oldTarget := t.backend.framebuffer
+	t.renderTextureBuffers = renderTextureBuffers{}
+	t.texture.t = 0
+	if err := t.create(width, height); err != nil {
+		t.renderTextureBuffers, *t.texture, t.samples = oldBuffers, oldTexture, oldSamples
+		t.backend.framebuffer = oldTarget
+		return err
+	}
+
+	t.backend.Activate()
+	oldBuffers.delete()
+	if oldTexture.t != 0 {
+		oldTexture.t.Delete()
+	}
+	invalidateGLStates()
+
+	t.SetSize(Vector2{float32(width), float32(height)})
+	return nil
+}
+
+// Display finishes the drawing: the texture is updated with what has been
+// drawn so far.
+func (t *RenderTexture) Display() {
+	if t.texture.destroyed {
+		return
+	}
+	t.Flush()
+	if t.activate() != nil {
+		return
+	}
+
+	// Resolve the multisampled framebuffer into the texture
+	if t.samples > 0 {
+		w, h := int(t.texture.size.X), int(t.texture.size.Y)
+		t.msFramebuffer.BindTarget(gl.READ_FRAMEBUFFER)
+		t.framebuffer.BindTarget(gl.DRAW_FRAMEBUFFER)
+		gl.BlitFramebuffer(0, 0, w, h, 0, 0, w, h, gl.COLOR_BUFFER_BIT, gl.NEAREST)
+		t.msFramebuffer.Bind()
+	}
+
+	// Make the result visible to the other contexts
+	gl.Flush()
+}
+
+// Destroy deletes the framebuffers and the texture right away, instead of
+// waiting for the garbage collector. Drawing into the render texture does
+// nothing afterwards, and its texture is drawn as if no texture was set.
+func (t *RenderTexture) Destroy() {
+	if t.texture.destroyed {
+		return
+	}
+	t.Flush()
+	flushBatches(t.texture)
+	t.destroy()
+	t.texture.Destroy()
+	t.RenderTarget.backend = closedBackend{}
+}
+
+// create creates the texture and the framebuffers
+func (t *RenderTexture) create(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return errors.New("sf: invalid render texture size")
+	}
+	if _, err := t.backend.Activate(); err != nil {
+		return err
+	}
+
+	// Create the texture, flipped since OpenGL's origin is at the bottom-left
+	t.texture.t = gl.GenTexture()
+	t.texture.t.Bind(gl.TEXTURE_2D)
+	t.texture.hasMipmap = false
+	t.texture.applyParams()
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, width, height, 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	t.texture.size = Vector2{float32(width), float32(height)}
+	t.texture.pixelsFlipped = true
+	t.texture.cacheId = nextTextureCacheId()
+
+	// Attach it to a framebuffer
+	t.framebuffer = gl.GenFramebuffer()
+	t.framebuffer.Bind()
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, t.texture.t, 0)
+	complete := gl.CheckFramebufferStatus(gl.FRAMEBUFFER) == gl.FRAMEBUFFER_COMPLETE
+	t.backend.framebuffer = t.framebuffer
+
+	// Draw into a multisampled framebuffer instead, if requested
+	if complete && t.samples > 0 {
+		var maxSamples [1]int32
+		gl.GetIntegerv(gl.MAX_SAMPLES, maxSamples[:])
+		if t.samples > int(maxSamples[0]) {
+			t.samples = int(maxSamples[0])
+		}
+	}
+	if complete && t.samples > 0 {
+		t.msColorbuffer = gl.GenRenderbuffer()
+		t.msColorbuffer.Bind()
+		gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, t.samples, gl.RGBA8, width, height)
+
+		t.msFramebuffer = gl.GenFramebuffer()
+		t.msFramebuffer.Bind()
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, t.msColorbuffer)
+		complete = gl.CheckFramebufferStatus(gl.FRAMEBUFFER) == gl.FRAMEBUFFER_COMPLETE
+		t.backend.framebuffer = t.msFramebuffer
+	}
+
+	// Give the framebuffer drawn into a packed depth and stencil buffer, the
+	// stencil holds the masks
+	if complete {
+		t.stencilbuffer = gl.GenRenderbuffer()
+		t.stencilbuffer.Bind()
+		if t.samples > 0 {
+			gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, t.samples, gl.DEPTH24_STENCIL8, width, height)
+		} else {
+			gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, width, height)
+		}
+		t.backend.framebuffer.Bind()
+		gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, t.stencilbuffer)
+		complete = gl.CheckFramebufferStatus(gl.FRAMEBUFFER) == gl.FRAMEBUFFER_COMPLETE
+	}
+
+	// We changed the bindings behind the render targets' back
+	invalidateGLStates()
+
+	if !complete {
+		t.destroy()
+		return errors.New("sf: can't create the render texture's framebuffer")
+	}
+	return nil
+}
+
+// destroy deletes the texture and the framebuffers
+func (t *RenderTexture) destroy() {
+	// Without a context (e.g. after Terminate) the objects are already gone
+	if _, err := t.backend.Activate(); err == nil {
+		t.renderTextureBuffers.delete()
+		if t.texture.t != 0 {
+			t.texture.t.Delete()
+		}
+	}
+	t.renderTextureBuffers = renderTextureBuffers{}
+	t.texture.t = 0
+
+	t.backend.framebuffer = 0
+	invalidateGLStates()
+}
+
+// delete deletes the OpenGL objects that were created
+func (b *renderTextureBuffers) delete() {
+	if b.msFramebuffer != 0 {
+		b.msFramebuffer.Delete()
+		b.msColorbuffer.Delete()
+	}
+	if b.stencilbuffer != 0 {
+		b.stencilbuffer.Delete()
+	}
+	if b.framebuffer != 0 {
+		b.framebuffer.Delete()
+	}
+}
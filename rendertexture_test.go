@@ -0,0 +1,55 @@
+package sf
+
+import (
+	"testing"
+)
+
+func TestRenderTextureResizeFailure(t *testing.T) {
+	// Only runs with an OpenGL context
+	newGLTarget(t)
+	rt, err := NewRenderTexture(4, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed resize keeps the render texture usable, at its previous size
+	name, framebuffer := rt.texture.t, rt.framebuffer
+	if err := rt.Resize(0, 4); err == nil {
+		t.Fatal("invalid size accepted")
+	}
+	if rt.Size() != (Vector2{4, 4}) || rt.Texture().Size() != (Vector2{4, 4}) {
+		t.Errorf("size %v, texture size %v", rt.Size(), rt.Texture().Size())
+	}
+	if rt.texture.t != name || rt.framebuffer != framebuffer || rt.backend.framebuffer != framebuffer {
+		t.Error("resources not restored")
+	}
+	rt.Clear(Color{0, 0, 0, 255})
+
+	if err := rt.Resize(8, 2); err != nil || rt.Size() != (Vector2{8, 2}) {
+		t.Errorf("resize: %v, size %v", err, rt.Size())
+	}
+}
+
+func TestRenderTextureDestroy(t *testing.T) {
+	// Only runs with an OpenGL context
+	newGLTarget(t)
+	rt, err := NewRenderTexture(4, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt.SetBatching(true)
+	rt.Render(quad(0, 0, 4, 4, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+
+	rt.Destroy()
+	if rt.framebuffer != 0 || !rt.Texture().destroyed {
+		t.Error("render texture not released")
+	}
+
+	// Drawing into it does nothing
+	rt.Clear(Color{0, 0, 0, 255})
+	rt.Render(quad(0, 0, 4, 4, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+	rt.Display()
+	if err := rt.Resize(8, 8); err == nil {
+		t.Error("destroyed render texture resized")
+	}
+}
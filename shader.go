@@ -0,0 +1,249 @@
+package sf
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/go-gl-legacy/gl"
+)
+
+// Shader is a GLSL program made of a vertex and/or a fragment shader, applied
+// to a draw through RenderStates.Shader.
+type Shader struct {
+	program        gl.Program
+	uniforms       map[string]gl.UniformLocation // Cached uniform locations
+	textures       []shaderTexture               // Textures bound to sampler uniforms
+	currentTexture gl.UniformLocation            // Location of the current texture's sampler, -1 if none
+	destroyed      bool                          // Has Destroy been called?
+}
+
+// A texture and the sampler uniform it's bound to
+type shaderTexture struct {
+	location gl.UniformLocation
+	texture  *Texture
+}
+
+// NewShaderFromMemory compiles and links a shader program from GLSL sources.
+// Either source may be empty, in which case the fixed-function pipeline is
+// used for that stage.
+func NewShaderFromMemory(vertexSrc, fragmentSrc string) (*Shader, error) {
+	if err := ensureGlContext(); err != nil {
+		return nil, err
+	}
+
+	program := gl.CreateProgram()
+	stages := []struct {
+		name string
+		typ  gl.GLenum
+		src  string
+	}{
+		{"vertex", gl.VERTEX_SHADER, vertexSrc},
+		{"fragment", gl.FRAGMENT_SHADER, fragmentSrc},
+	}
+	for _, stage := range stages {
+		if stage.src == "" {
+			continue
+		}
+
+		shader := gl.CreateShader(stage.typ)
+		shader.Source(stage.src)
+		shader.Compile()
+		if shader.Get(gl.COMPILE_STATUS) == gl.FALSE {
+			log := shader.GetInfoLog()
+			shader.Delete()
+			program.Delete()
+			return nil, fmt.Errorf("sf: failed to compile %s shader: %s", stage.name, log)
+		}
+
+		// The shader stays alive as long as it's attached to the program
+		program.AttachShader(shader)
+		shader.Delete()
+	}
+
+	program.Link()
+	if program.Get(gl.LINK_STATUS) == gl.FALSE {
+		log := program.GetInfoLog()
+		program.Delete()
+		return nil, fmt.Errorf("sf: failed to link shader: %s", log)
+	}
+
+	s := &Shader{program: program, uniforms: make(map[string]gl.UniformLocation), currentTexture: -1}
+	if shaderFinalizers {
+		runtime.SetFinalizer(s, finalizeShader)
+	}
+	return s, nil
+}
+
+// NewShaderFromFile compiles and links a shader program from GLSL source
+// files. Either path may be empty, in which case the fixed-function pipeline
+// is used for that stage.
+func NewShaderFromFile(vertexPath, fragmentPath string) (*Shader, error) {
+	var srcs [2]string
+	for i, path := range [2]string{vertexPath, fragmentPath} {
+		if path == "" {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		srcs[i] = string(src)
+	}
+
+	return NewShaderFromMemory(srcs[0], srcs[1])
+}
+
+// Destroy deletes the OpenGL program right away, instead of waiting for the
+// garbage collector. The shader must not be used afterwards: render targets
+// draw as if no shader was set.
+func (s *Shader) Destroy() {
+	if s.program != 0 && ensureGlContext() == nil {
+		s.program.Delete()
+
+		// The program may have been in use behind the render targets' back
+		invalidateGLStates()
+	}
+
+	*s = Shader{currentTexture: -1, destroyed: true}
+	runtime.SetFinalizer(s, nil)
+}
+
+// SetFloat sets a float uniform
+func (s *Shader) SetFloat(name string, x float32) {
+	s.setUniform(name, func(location gl.UniformLocation) {
+		location.Uniform1f(x)
+	})
+}
+
+// SetVector2 sets a vec2 uniform
+func (s *Shader) SetVector2(name string, v Vector2) {
+	s.setUniform(name, func(location gl.UniformLocation) {
+		location.Uniform2f(v.X, v.Y)
+	})
+}
+
+// SetColor sets a vec4 uniform, with the components normalized to [0 .. 1]
+func (s *Shader) SetColor(name string, c Color) {
+	s.setUniform(name, func(location gl.UniformLocation) {
+		location.Uniform4f(float32(c.R)/255, float32(c.G)/255, float32(c.B)/255, float32(c.A)/255)
+	})
+}
+
+// SetTransform sets a mat4 uniform
+func (s *Shader) SetTransform(name string, t Transform) {
+	s.setUniform(name, func(location gl.UniformLocation) {
+		location.UniformMatrix4f(false, &t.Matrix)
+	})
+}
+
+// SetTexture sets a sampler2D uniform. The texture units are assigned when
+// the shader is bound; texture coordinates are normalized.
+func (s *Shader) SetTexture(name string, texture *Texture) {
+	location := s.uniformLocation(name)
+	if location == -1 {
+		return
+	}
+
+	for i := range s.textures {
+		if s.textures[i].location == location {
+			s.textures[i].texture = texture
+			return
+		}
+	}
+	s.textures = append(s.textures, shaderTexture{location, texture})
+}
+
+// SetCurrentTexture sets a sampler2D uniform to the texture of the object
+// being drawn, i.e. RenderStates.Texture
+func (s *Shader) SetCurrentTexture(name string) {
+	s.currentTexture = s.uniformLocation(name)
+}
+
+// uniformLocation returns the location of a uniform, or -1 if the program
+// doesn't use it. It also makes sure an OpenGL context is current for the
+// caller, and returns -1 if there's none, so that the uniform is skipped.
+func (s *Shader) uniformLocation(name string) gl.UniformLocation {
+	if s.destroyed || ensureGlContext() != nil {
+		return -1
+	}
+	if location, ok := s.uniforms[name]; ok {
+		return location
+	}
+
+	location := s.program.GetUniformLocation(name)
+	s.uniforms[name] = location
+	return location
+}
+
+// setUniform sets a uniform of the program, which must be in use while doing so
+func (s *Shader) setUniform(name string, set func(location gl.UniformLocation)) {
+	location := s.uniformLocation(name)
+	if location == -1 {
+		return
+	}
+
+	var current [1]int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, current[:])
+	s.program.Use()
+	set(location)
+	gl.Program(current[0]).Use()
+}
+
+// bind makes the program current and binds its textures
+func (s *Shader) bind() {
+	s.program.Use()
+
+	// Bind the textures to the units following the one of the current texture
+	for i, t := range s.textures {
+		unit := i + 1
+		t.location.Uniform1i(unit)
+		gl.ActiveTexture(gl.TEXTURE0 + gl.GLenum(unit))
+		t.texture.Bind(CoordNormalized)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+
+	if s.currentTexture != -1 {
+		s.currentTexture.Uniform1i(0)
+	}
+}
+
+// Programs of the shaders garbage collected without being destroyed, waiting
+// to be deleted
+var shadersToDelete struct {
+	sync.Mutex
+	programs []gl.Program
+}
+
+// Are finalizers attached to the new shaders?
+var shaderFinalizers bool
+
+// SetShaderFinalizers enables or disables the deletion of the OpenGL programs
+// of the shaders created afterwards, once they're garbage collected. Like
+// with SetTextureFinalizers, the deletion is deferred to the next time a
+// render target draws. Destroy frees the program right away.
+func SetShaderFinalizers(enabled bool) {
+	shaderFinalizers = enabled
+}
+
+func finalizeShader(s *Shader) {
+	if s.program != 0 {
+		shadersToDelete.Lock()
+		shadersToDelete.programs = append(shadersToDelete.programs, s.program)
+		shadersToDelete.Unlock()
+	}
+}
+
+// deleteFinalizedShaders deletes the programs queued by the finalizers. It
+// must be called on the rendering thread, with an OpenGL context current.
+func deleteFinalizedShaders() {
+	shadersToDelete.Lock()
+	programs := shadersToDelete.programs
+	shadersToDelete.programs = nil
+	shadersToDelete.Unlock()
+
+	for _, program := range programs {
+		program.Delete()
+	}
+}
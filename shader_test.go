@@ -0,0 +1,71 @@
+package sf
+
+import (
+	"image"
+	"testing"
+
+	"github.com/go-gl-legacy/gl"
+)
+
+// Backend recording the shaders bound
+type shaderRecorder struct {
+	Backend
+	bound []*Shader
+}
+
+func (b *shaderRecorder) BindShader(shader *Shader) {
+	b.bound = append(b.bound, shader)
+	b.Backend.BindShader(shader)
+}
+
+func TestShaderDestroy(t *testing.T) {
+	backend := &shaderRecorder{Backend: NewSoftwareBackend(image.NewRGBA(image.Rect(0, 0, 4, 4)))}
+	target := NewRenderTargetWithBackend(Vector2{4, 4}, backend)
+	target.SetBatching(true)
+
+	// Never linked, so that Destroy doesn't need OpenGL
+	shader := &Shader{uniforms: make(map[string]gl.UniformLocation), currentTexture: -1}
+	states := DefaultRenderStates()
+	states.Shader = shader
+	target.Render(quad(0, 0, 4, 4, Color{255, 255, 255, 255}), Quads, states)
+	shader.Destroy()
+	if shader.program != 0 || shader.uniformLocation("color") != -1 {
+		t.Error("shader not released")
+	}
+
+	// The pending batch is drawn without the destroyed shader
+	backend.bound = nil
+	target.Flush()
+	for _, s := range backend.bound {
+		if s != nil {
+			t.Error("destroyed shader bound")
+		}
+	}
+}
+
+func TestShaderFinalizer(t *testing.T) {
+	// Pretend it was linked, the finalizer must only queue it
+	finalizeShader(&Shader{program: 42})
+	shadersToDelete.Lock()
+	queued := shadersToDelete.programs
+	shadersToDelete.programs = nil
+	shadersToDelete.Unlock()
+	if len(queued) != 1 || queued[0] != 42 {
+		t.Errorf("queued programs: %v", queued)
+	}
+}
+
+func TestShaderUniformsWithoutContext(t *testing.T) {
+	if glfwInitialized {
+		t.Skip("OpenGL is available")
+	}
+
+	// Without a context the uniforms are skipped instead of calling OpenGL
+	shader := &Shader{program: 1, uniforms: make(map[string]gl.UniformLocation), currentTexture: -1}
+	shader.SetColor("color", Color{255, 0, 0, 255})
+	shader.SetTexture("texture", &Texture{})
+	shader.SetCurrentTexture("current")
+	if len(shader.uniforms) != 0 || len(shader.textures) != 0 || shader.currentTexture != -1 {
+		t.Error("uniforms set without an OpenGL context")
+	}
+}
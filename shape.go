@@ -0,0 +1,262 @@
+package sf
+
+// Shape is what RectangleShape, CircleShape and ConvexShape have in common:
+// a convex polygon drawn with a fill and an outline. The embedding shapes
+// compute the points of the polygon, Shape generates the geometry.
+type Shape struct {
+	Transformable
+	points           []Vector2 // Points of the polygon, in local coordinates
+	texture          *Texture  // Texture of the fill
+	textureRect      Rect      // Part of the texture mapped to the fill
+	fillColor        Color     // Color of the fill
+	outlineColor     Color     // Color of the outline
+	outlineThickness float32   // Thickness of the outline, grows outwards if positive
+	vertices         []Vertex  // Triangles of the fill
+	outlineVertices  []Vertex  // Triangles of the outline
+	insideBounds     Rect      // Bounding rectangle of the fill
+	bounds           Rect      // Bounding rectangle of the fill and the outline
+}
+
+// init sets the default attributes, it must be called by the shapes' constructors
+func (s *Shape) init() {
+	s.Transformable = *NewTransformable()
+	s.fillColor = Color{255, 255, 255, 255}
+	s.outlineColor = Color{255, 255, 255, 255}
+}
+
+// SetTexture sets the texture of the fill. The texture rect is set to the
+// whole texture if resetRect is true, or if the shape had no texture.
+func (s *Shape) SetTexture(texture *Texture, resetRect bool) {
+	if texture != nil && (resetRect || s.texture == nil) {
+		s.SetTextureRect(Rect{0, 0, texture.Size().X, texture.Size().Y})
+	}
+	s.texture = texture
+}
+
+func (s *Shape) Texture() *Texture {
+	return s.texture
+}
+
+// SetTextureRect sets the part of the texture mapped to the fill
+func (s *Shape) SetTextureRect(rect Rect) {
+	s.textureRect = rect
+	s.updateTexCoords()
+}
+
+func (s *Shape) TextureRect() Rect {
+	return s.textureRect
+}
+
+func (s *Shape) SetFillColor(color Color) {
+	s.fillColor = color
+	for i := range s.vertices {
+		s.vertices[i].Color = color
+	}
+}
+
+func (s *Shape) FillColor() Color {
+	return s.fillColor
+}
+
+func (s *Shape) SetOutlineColor(color Color) {
+	s.outlineColor = color
+	for i := range s.outlineVertices {
+		s.outlineVertices[i].Color = color
+	}
+}
+
+func (s *Shape) OutlineColor() Color {
+	return s.outlineColor
+}
+
+// SetOutlineThickness sets the thickness of the outline. It grows outwards
+// if positive, inwards if negative. 0 disables the outline.
+func (s *Shape) SetOutlineThickness(thickness float32) {
+	s.outlineThickness = thickness
+	s.updateOutline()
+}
+
+func (s *Shape) OutlineThickness() float32 {
+	return s.outlineThickness
+}
+
+// PointCount returns the number of points of the polygon
+func (s *Shape) PointCount() int {
+	return len(s.points)
+}
+
+// Point returns a point of the polygon, in local coordinates
+func (s *Shape) Point(index int) Vector2 {
+	return s.points[index]
+}
+
+// LocalBounds returns the bounding rectangle of the shape and its outline,
+// ignoring its transform
+func (s *Shape) LocalBounds() Rect {
+	return s.bounds
+}
+
+// GlobalBounds returns the bounding rectangle of the shape and its outline,
+// once transformed
+func (s *Shape) GlobalBounds() Rect {
+	transform := s.Transform()
+	return transform.TransformRect(s.LocalBounds())
+}
+
+func (s *Shape) Render(target *RenderTarget, states RenderStates) {
+	states.Transform.Combine(s.Transform())
+
+	// Render the inside
+	states.Texture = s.texture
+	target.Render(s.vertices, Triangles, states)
+
+	// Render the outline
+	if s.outlineThickness != 0 {
+		states.Texture = nil
+		target.Render(s.outlineVertices, Triangles, states)
+	}
+}
+
+// update regenerates the geometry from the points of the polygon
+func (s *Shape) update(points []Vector2) {
+	s.points = append(s.points[:0], points...)
+	if len(points) < 3 {
+		s.vertices = s.vertices[:0]
+		s.outlineVertices = s.outlineVertices[:0]
+		s.insideBounds = Rect{}
+		s.bounds = Rect{}
+		return
+	}
+
+	s.insideBounds = boundingRect(points)
+
+	// The polygon is convex, fan it out from its first point
+	s.vertices = s.vertices[:0]
+	for i := 2; i < len(points); i++ {
+		s.vertices = append(s.vertices,
+			Vertex{points[0], s.fillColor, Vector2{}},
+			Vertex{points[i-1], s.fillColor, Vector2{}},
+			Vertex{points[i], s.fillColor, Vector2{}})
+	}
+
+	s.updateTexCoords()
+	s.updateOutline()
+}
+
+// updateTexCoords maps the texture rect over the inside bounds
+func (s *Shape) updateTexCoords() {
+	for i := range s.vertices {
+		var xratio, yratio float32
+		if s.insideBounds.W > 0 {
+			xratio = (s.vertices[i].Pos.X - s.insideBounds.Left) / s.insideBounds.W
+		}
+		if s.insideBounds.H > 0 {
+			yratio = (s.vertices[i].Pos.Y - s.insideBounds.Top) / s.insideBounds.H
+		}
+		s.vertices[i].TexCoords.X = s.textureRect.Left + s.textureRect.W*xratio
+		s.vertices[i].TexCoords.Y = s.textureRect.Top + s.textureRect.H*yratio
+	}
+}
+
+// updateOutline generates the outline triangles, one pair per edge
+func (s *Shape) updateOutline() {
+	s.outlineVertices = s.outlineVertices[:0]
+	if s.outlineThickness == 0 || len(s.points) < 3 {
+		s.bounds = s.insideBounds
+		return
+	}
+
+	count := len(s.points)
+	center := Vector2{s.insideBounds.Left + s.insideBounds.W/2, s.insideBounds.Top + s.insideBounds.H/2}
+
+	// Compute the outer point of each point of the polygon
+	outer := make([]Vector2, count)
+	for i := range s.points {
+		p0 := s.points[(i+count-1)%count]
+		p1 := s.points[i]
+		p2 := s.points[(i+1)%count]
+
+		// Normals of the two edges sharing the point, pointing outwards
+		n1 := edgeNormal(p0, p1)
+		n2 := edgeNormal(p1, p2)
+		if dot(n1, center.Sub(p1)) > 0 {
+			n1 = n1.Mult(-1)
+		}
+		if dot(n2, center.Sub(p1)) > 0 {
+			n2 = n2.Mult(-1)
+		}
+
+		// Move along the combined normal, so that both edges are offset by the
+		// thickness. Antiparallel edges (a hairpin) have no such point, they're
+		// only offset along the first normal.
+		factor := 1 + dot(n1, n2)
+		normal := n1
+		if factor > 1e-6 {
+			normal = n1.Add(n2).Div(factor)
+		}
+		outer[i] = p1.Add(normal.Mult(s.outlineThickness))
+	}
+
+	for i := range s.points {
+		j := (i + 1) % count
+		s.outlineVertices = append(s.outlineVertices,
+			Vertex{s.points[i], s.outlineColor, Vector2{}},
+			Vertex{outer[i], s.outlineColor, Vector2{}},
+			Vertex{s.points[j], s.outlineColor, Vector2{}},
+			Vertex{s.points[j], s.outlineColor, Vector2{}},
+			Vertex{outer[i], s.outlineColor, Vector2{}},
+			Vertex{outer[j], s.outlineColor, Vector2{}})
+	}
+
+	// The outline may grow past the inside
+	bounds := boundingRect(outer)
+	left := min32(bounds.Left, s.insideBounds.Left)
+	top := min32(bounds.Top, s.insideBounds.Top)
+	right := max32(bounds.Left+bounds.W, s.insideBounds.Left+s.insideBounds.W)
+	bottom := max32(bounds.Top+bounds.H, s.insideBounds.Top+s.insideBounds.H)
+	s.bounds = Rect{left, top, right - left, bottom - top}
+}
+
+// edgeNormal returns the unit normal of the edge (p1, p2)
+func edgeNormal(p1, p2 Vector2) Vector2 {
+	normal := Vector2{p1.Y - p2.Y, p2.X - p1.X}
+	if length := normal.Length(); length != 0 {
+		normal = normal.Div(length)
+	}
+	return normal
+}
+
+func dot(v1, v2 Vector2) float32 {
+	return v1.X*v2.X + v1.Y*v2.Y
+}
+
+// boundingRect returns the smallest rectangle containing the points
+func boundingRect(points []Vector2) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+
+	left, top := points[0].X, points[0].Y
+	right, bottom := left, top
+	for _, p := range points[1:] {
+		left = min32(left, p.X)
+		top = min32(top, p.Y)
+		right = max32(right, p.X)
+		bottom = max32(bottom, p.Y)
+	}
+	return Rect{left, top, right - left, bottom - top}
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
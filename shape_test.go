@@ -0,0 +1,93 @@
+package sf
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestShapeBounds(t *testing.T) {
+	rect := NewRectangleShape(Vector2{10, 20})
+	rect.SetOutlineThickness(2)
+	rect.SetPositionXY(5, 5)
+	if !approxRect(rect.LocalBounds(), Rect{-2, -2, 14, 24}) {
+		t.Errorf("rectangle local bounds: %v", rect.LocalBounds())
+	}
+	if !approxRect(rect.GlobalBounds(), Rect{3, 3, 14, 24}) {
+		t.Errorf("rectangle global bounds: %v", rect.GlobalBounds())
+	}
+
+	circle := NewCircleShape(5, 4)
+	if circle.PointCount() != 4 {
+		t.Errorf("circle point count: %d", circle.PointCount())
+	}
+	if !approxRect(circle.LocalBounds(), Rect{0, 0, 10, 10}) {
+		t.Errorf("circle local bounds: %v", circle.LocalBounds())
+	}
+
+	convex := NewConvexShape(3)
+	convex.SetPoint(1, Vector2{4, 0})
+	convex.SetPoint(2, Vector2{0, 3})
+	if convex.Point(1) != (Vector2{4, 0}) || !approxRect(convex.LocalBounds(), Rect{0, 0, 4, 3}) {
+		t.Errorf("convex local bounds: %v", convex.LocalBounds())
+	}
+}
+
+func TestShapeDegenerate(t *testing.T) {
+	if NewCircleShape(5, -1).PointCount() != 0 || NewConvexShape(-3).PointCount() != 0 {
+		t.Error("negative point count not clamped")
+	}
+
+	// The polygon backtracks on itself, the outline must stay finite
+	convex := NewConvexShape(3)
+	convex.SetPoint(1, Vector2{4, 0})
+	convex.SetPoint(2, Vector2{2, 0})
+	convex.SetOutlineThickness(1)
+	for _, v := range convex.outlineVertices {
+		if math.IsNaN(float64(v.Pos.X)) || math.IsInf(float64(v.Pos.X), 0) ||
+			math.IsNaN(float64(v.Pos.Y)) || math.IsInf(float64(v.Pos.Y), 0) {
+			t.Fatalf("outline vertex %v", v.Pos)
+		}
+	}
+}
+
+func TestShapeDraw(t *testing.T) {
+	target, img := newSoftwareTarget(10, 10)
+	target.Clear(Color{0, 0, 0, 255})
+
+	rect := NewRectangleShape(Vector2{4, 4})
+	rect.SetPositionXY(3, 3)
+	rect.SetFillColor(Color{255, 0, 0, 255})
+	rect.SetOutlineColor(Color{0, 0, 255, 255})
+	rect.SetOutlineThickness(1)
+	target.Draw(rect)
+
+	// A 4x4 fill surrounded by a one pixel wide outline, nothing blended twice
+	if got := countPixels(img, color.RGBA{255, 0, 0, 255}); got != 16 {
+		t.Errorf("%d fill pixels, want 16", got)
+	}
+	if got := countPixels(img, color.RGBA{0, 0, 255, 255}); got != 36-16 {
+		t.Errorf("%d outline pixels, want 20", got)
+	}
+	if img.RGBAAt(2, 2) != (color.RGBA{0, 0, 255, 255}) || img.RGBAAt(1, 1) != (color.RGBA{0, 0, 0, 255}) {
+		t.Error("outline misplaced")
+	}
+
+	// Textured fill
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{0, 255, 0, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{255, 255, 255, 255})
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rect.SetOutlineThickness(0)
+	rect.SetFillColor(Color{255, 255, 255, 255})
+	rect.SetTexture(tex, false)
+	rect.SetTextureRect(Rect{0, 0, 1, 1})
+	target.Draw(rect)
+	if got := countPixels(img, color.RGBA{0, 255, 0, 255}); got != 16 {
+		t.Errorf("%d textured pixels, want 16", got)
+	}
+}
@@ -0,0 +1,459 @@
+package sf
+
+import (
+	"image"
+	"math"
+)
+
+// States of the software backend that PushStates/PopStates save and restore
+type softwareStates struct {
+	viewport   image.Rectangle // Viewport, in image coordinates
+	projection Transform       // Projection matrix
+	modelView  Transform       // Model-view matrix
+	blendMode  BlendMode       // Current blending mode
+	texture    *Texture        // Currently bound texture
+	scissor    image.Rectangle // Area drawing is restricted to, in image coordinates
+	scissorOn  bool            // Is drawing restricted to the scissor area?
+	stencil    StencilMode     // Use of the stencil mask
+}
+
+// Pure Go implementation of Backend rasterizing into an image
+type softwareBackend struct {
+	softwareStates
+
+	img   *image.RGBA
+	mask  []bool // Stencil mask of each pixel, allocated on first use
+	stack []softwareStates
+}
+
+// A vertex once projected to image coordinates, with its color normalized
+type rasterVertex struct {
+	x, y       float64
+	r, g, b, a float64
+	u, v       float64
+}
+
+// NewSoftwareBackend returns a Backend that rasterizes on the CPU into img,
+// following the same rules as the OpenGL backend. It doesn't need any
+// windowing system or GL context.
+//
+// The image is treated as a framebuffer: it holds the channel values that
+// OpenGL would write, which only form valid premultiplied colors where alpha
+// is 255 (e.g. after clearing with an opaque color).
+func NewSoftwareBackend(img *image.RGBA) Backend {
+	b := &softwareBackend{img: img}
+	b.ResetStates()
+	b.viewport = img.Bounds()
+	return b
+}
+
+// Activate reports false: the software backend's states belong to it alone.
+func (b *softwareBackend) Activate() (bool, error) {
+	return false, nil
+}
+
+func (b *softwareBackend) Clear(color Color) {
+	bounds := b.img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			i := b.img.PixOffset(x, y)
+			b.img.Pix[i+0] = color.R
+			b.img.Pix[i+1] = color.G
+			b.img.Pix[i+2] = color.B
+			b.img.Pix[i+3] = color.A
+		}
+	}
+}
+
+func (b *softwareBackend) ClearStencil() {
+	for i := range b.mask {
+		b.mask[i] = false
+	}
+}
+
+func (b *softwareBackend) ResetStates() {
+	b.projection = IdentityTransform()
+	b.modelView = IdentityTransform()
+	b.blendMode = BlendAlpha()
+	b.texture = nil
+	b.scissorOn = false
+	b.stencil = StencilNone
+}
+
+func (b *softwareBackend) PushStates() {
+	b.stack = append(b.stack, b.softwareStates)
+}
+
+func (b *softwareBackend) PopStates() {
+	if len(b.stack) > 0 {
+		b.softwareStates = b.stack[len(b.stack)-1]
+		b.stack = b.stack[:len(b.stack)-1]
+	}
+}
+
+func (b *softwareBackend) SetViewport(x, y, w, h int) {
+	b.viewport = b.toImageRect(x, y, w, h)
+}
+
+// toImageRect converts a rectangle with a bottom-left origin to image coordinates
+func (b *softwareBackend) toImageRect(x, y, w, h int) image.Rectangle {
+	bounds := b.img.Bounds()
+	top := bounds.Dy() - (y + h)
+	return image.Rect(x, top, x+w, top+h).Add(bounds.Min)
+}
+
+func (b *softwareBackend) SetScissor(enabled bool, x, y, w, h int) {
+	b.scissorOn = enabled
+	if enabled {
+		b.scissor = b.toImageRect(x, y, w, h)
+	}
+}
+
+func (b *softwareBackend) SetStencilMode(mode StencilMode) {
+	b.stencil = mode
+	if mode != StencilNone && b.mask == nil {
+		b.mask = make([]bool, b.img.Bounds().Dx()*b.img.Bounds().Dy())
+	}
+}
+
+func (b *softwareBackend) SetProjection(transform Transform) {
+	b.projection = transform
+}
+
+func (b *softwareBackend) SetModelView(transform Transform) {
+	b.modelView = transform
+}
+
+func (b *softwareBackend) SetBlendMode(mode BlendMode) {
+	b.blendMode = mode
+}
+
+func (b *softwareBackend) BindTexture(texture *Texture) {
+	b.texture = texture
+}
+
+// BindShader does nothing: GLSL can't run on the CPU, so shaders are ignored
+// by the software backend.
+func (b *softwareBackend) BindShader(shader *Shader) {
+}
+
+func (b *softwareBackend) DrawPrimitives(verts []Vertex, primType PrimitiveType) {
+	rv := make([]rasterVertex, len(verts))
+	for i := range verts {
+		rv[i] = b.project(&verts[i])
+	}
+
+	switch primType {
+	case Points:
+		for i := range rv {
+			b.drawPoint(&rv[i])
+		}
+	case Lines:
+		for i := 1; i < len(rv); i += 2 {
+			b.drawLine(&rv[i-1], &rv[i])
+		}
+	case LineStrip:
+		for i := 1; i < len(rv); i++ {
+			b.drawLine(&rv[i-1], &rv[i])
+		}
+	case Triangles:
+		for i := 2; i < len(rv); i += 3 {
+			b.drawTriangle(&rv[i-2], &rv[i-1], &rv[i])
+		}
+	case TriangleStrip:
+		for i := 2; i < len(rv); i++ {
+			b.drawTriangle(&rv[i-2], &rv[i-1], &rv[i])
+		}
+	case TriangleFan:
+		for i := 2; i < len(rv); i++ {
+			b.drawTriangle(&rv[0], &rv[i-1], &rv[i])
+		}
+	case Quads:
+		for i := 3; i < len(rv); i += 4 {
+			b.drawTriangle(&rv[i-3], &rv[i-2], &rv[i-1])
+			b.drawTriangle(&rv[i-3], &rv[i-1], &rv[i])
+		}
+	}
+}
+
+func (b *softwareBackend) DrawBuffer(vb *VertexBuffer, first, count int, primType PrimitiveType) {
+	b.DrawPrimitives(vb.verts[first:first+count], primType)
+}
+
+// project transforms a vertex to image coordinates
+func (b *softwareBackend) project(v *Vertex) rasterVertex {
+	p := b.modelView.TransformPoint(v.Pos)
+	p = b.projection.TransformPoint(p)
+
+	// Normalized device coordinates -> viewport, with the Y axis pointing down
+	vp := b.viewport
+	x := float64(vp.Min.X) + (float64(p.X)+1)/2*float64(vp.Dx())
+	y := float64(vp.Min.Y) + (1-float64(p.Y))/2*float64(vp.Dy())
+
+	return rasterVertex{x, y,
+		float64(v.Color.R) / 255, float64(v.Color.G) / 255,
+		float64(v.Color.B) / 255, float64(v.Color.A) / 255,
+		float64(v.TexCoords.X), float64(v.TexCoords.Y)}
+}
+
+// clipRect returns the area pixels may be written to
+func (b *softwareBackend) clipRect() image.Rectangle {
+	clip := b.viewport.Intersect(b.img.Bounds())
+	if b.scissorOn {
+		clip = clip.Intersect(b.scissor)
+	}
+	return clip
+}
+
+func (b *softwareBackend) drawPoint(v *rasterVertex) {
+	x, y := int(math.Floor(v.x)), int(math.Floor(v.y))
+	if image.Pt(x, y).In(b.clipRect()) {
+		b.shade(x, y, v)
+	}
+}
+
+func (b *softwareBackend) drawLine(v0, v1 *rasterVertex) {
+	clip := b.clipRect()
+	dx, dy := v1.x-v0.x, v1.y-v0.y
+	steps := int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))))
+
+	// Like OpenGL, the last pixel of the line isn't drawn
+	for i := 0; i < steps; i++ {
+		t := float64(i) / float64(steps)
+		frag := lerpVertex(v0, v1, t)
+		x, y := int(math.Floor(frag.x)), int(math.Floor(frag.y))
+		if image.Pt(x, y).In(clip) {
+			b.shade(x, y, &frag)
+		}
+	}
+}
+
+func (b *softwareBackend) drawTriangle(v0, v1, v2 *rasterVertex) {
+	area := edge(v0, v1, v2.x, v2.y)
+	if area == 0 {
+		return
+	}
+	// Make the winding consistent so that shared edges are owned by one triangle only
+	if area < 0 {
+		v1, v2 = v2, v1
+		area = -area
+	}
+
+	// Bounding box of the triangle, clipped to the drawable area
+	minX := math.Floor(math.Min(v0.x, math.Min(v1.x, v2.x)))
+	minY := math.Floor(math.Min(v0.y, math.Min(v1.y, v2.y)))
+	maxX := math.Ceil(math.Max(v0.x, math.Max(v1.x, v2.x)))
+	maxY := math.Ceil(math.Max(v0.y, math.Max(v1.y, v2.y)))
+	box := image.Rect(int(minX), int(minY), int(maxX), int(maxY)).Intersect(b.clipRect())
+
+	for y := box.Min.Y; y < box.Max.Y; y++ {
+		py := float64(y) + 0.5
+		for x := box.Min.X; x < box.Max.X; x++ {
+			px := float64(x) + 0.5
+
+			// Sample at the pixel center
+			w0 := edge(v1, v2, px, py)
+			w1 := edge(v2, v0, px, py)
+			w2 := edge(v0, v1, px, py)
+			if !inside(w0, v1, v2) || !inside(w1, v2, v0) || !inside(w2, v0, v1) {
+				continue
+			}
+
+			l0, l1, l2 := w0/area, w1/area, w2/area
+			frag := rasterVertex{px, py,
+				l0*v0.r + l1*v1.r + l2*v2.r,
+				l0*v0.g + l1*v1.g + l2*v2.g,
+				l0*v0.b + l1*v1.b + l2*v2.b,
+				l0*v0.a + l1*v1.a + l2*v2.a,
+				l0*v0.u + l1*v1.u + l2*v2.u,
+				l0*v0.v + l1*v1.v + l2*v2.v}
+			b.shade(x, y, &frag)
+		}
+	}
+}
+
+// edge returns twice the signed area of the triangle (a, b, (x, y))
+func edge(a, b *rasterVertex, x, y float64) float64 {
+	return (b.x-a.x)*(y-a.y) - (b.y-a.y)*(x-a.x)
+}
+
+// inside tells if a point with edge value w relative to the edge (a, b) is
+// covered. Points exactly on the edge belong to only one of the two triangles
+// sharing it, so that nothing is blended twice.
+func inside(w float64, a, b *rasterVertex) bool {
+	if w != 0 {
+		return w > 0
+	}
+	dx, dy := b.x-a.x, b.y-a.y
+	return dy > 0 || (dy == 0 && dx < 0)
+}
+
+func lerpVertex(v0, v1 *rasterVertex, t float64) rasterVertex {
+	return rasterVertex{v0.x + (v1.x-v0.x)*t, v0.y + (v1.y-v0.y)*t,
+		v0.r + (v1.r-v0.r)*t, v0.g + (v1.g-v0.g)*t,
+		v0.b + (v1.b-v0.b)*t, v0.a + (v1.a-v0.a)*t,
+		v0.u + (v1.u-v0.u)*t, v0.v + (v1.v-v0.v)*t}
+}
+
+// shade computes the color of a fragment and blends it into the image
+func (b *softwareBackend) shade(x, y int, frag *rasterVertex) {
+	// Test the stencil mask
+	bounds := b.img.Bounds()
+	m := (y-bounds.Min.Y)*bounds.Dx() + x - bounds.Min.X
+	if (b.stencil == StencilInside && !b.mask[m]) || (b.stencil == StencilOutside && b.mask[m]) {
+		return
+	}
+
+	src := [4]float64{frag.r, frag.g, frag.b, frag.a}
+
+	// Modulate by the texture color
+	if b.texture != nil && b.texture.pixels != nil {
+		texel := b.sample(frag.u, frag.v)
+		for i := range src {
+			src[i] *= texel[i]
+		}
+	}
+
+	// Only write the mask, where the fragment isn't fully transparent
+	if b.stencil == StencilWrite {
+		if src[3] > 0 {
+			b.mask[m] = true
+		}
+		return
+	}
+
+	i := b.img.PixOffset(x, y)
+	pix := b.img.Pix[i : i+4]
+	var dst [4]float64
+	for c := range dst {
+		dst[c] = float64(pix[c]) / 255
+	}
+
+	out := blend(b.blendMode, src, dst)
+	for c := range out {
+		pix[c] = uint8(math.Floor(clamp01(out[c])*255 + 0.5))
+	}
+}
+
+// sample returns the normalized color of the bound texture at the given
+// pixel coordinates, filtered like OpenGL would
+func (b *softwareBackend) sample(u, v float64) [4]float64 {
+	t := b.texture
+	w, h := float64(t.size.X), float64(t.size.Y)
+
+	// Same mapping as the texture matrix set up by Texture.Bind
+	u /= w
+	v /= h
+	if t.pixelsFlipped {
+		v = 1 - v
+	}
+	x, y := u*w, v*h
+
+	if !t.isSmooth {
+		return b.texel(int(math.Floor(x)), int(math.Floor(y)))
+	}
+
+	// Linear filtering: interpolate between the centers of the 4 nearest texels
+	x -= 0.5
+	y -= 0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	c00 := b.texel(int(x0), int(y0))
+	c10 := b.texel(int(x0)+1, int(y0))
+	c01 := b.texel(int(x0), int(y0)+1)
+	c11 := b.texel(int(x0)+1, int(y0)+1)
+
+	var out [4]float64
+	for i := range out {
+		top := c00[i]*(1-fx) + c10[i]*fx
+		bottom := c01[i]*(1-fx) + c11[i]*fx
+		out[i] = top*(1-fy) + bottom*fy
+	}
+	return out
+}
+
+// texel returns the normalized color of a pixel of the bound texture,
+// wrapped or clamped to the edges depending on the repeat mode
+func (b *softwareBackend) texel(x, y int) [4]float64 {
+	t := b.texture
+	bounds := t.pixels.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	if t.isRepeated {
+		x = (x%w + w) % w
+		y = (y%h + h) % h
+	} else {
+		x = clampInt(x, 0, w-1)
+		y = clampInt(y, 0, h-1)
+	}
+
+	c := t.pixels.NRGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+	return [4]float64{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255}
+}
+
+// blend combines a source and destination color the way OpenGL does for the
+// given mode
+func blend(mode BlendMode, src, dst [4]float64) [4]float64 {
+	var out [4]float64
+	for c := range out {
+		srcFactor, dstFactor, equation := mode.ColorSrcFactor, mode.ColorDstFactor, mode.ColorEquation
+		if c == 3 {
+			srcFactor, dstFactor, equation = mode.AlphaSrcFactor, mode.AlphaDstFactor, mode.AlphaEquation
+		}
+
+		s := src[c] * blendFactor(srcFactor, c, src, dst)
+		d := dst[c] * blendFactor(dstFactor, c, src, dst)
+		switch equation {
+		case EquationAdd:
+			out[c] = s + d
+		case EquationSubtract:
+			out[c] = s - d
+		case EquationReverseSubtract:
+			out[c] = d - s
+		case EquationMin:
+			out[c] = math.Min(src[c], dst[c])
+		case EquationMax:
+			out[c] = math.Max(src[c], dst[c])
+		}
+	}
+	return out
+}
+
+// blendFactor returns the value of a blend factor for the channel c
+func blendFactor(factor BlendFactor, c int, src, dst [4]float64) float64 {
+	switch factor {
+	case FactorOne:
+		return 1
+	case FactorSrcColor:
+		return src[c]
+	case FactorOneMinusSrcColor:
+		return 1 - src[c]
+	case FactorDstColor:
+		return dst[c]
+	case FactorOneMinusDstColor:
+		return 1 - dst[c]
+	case FactorSrcAlpha:
+		return src[3]
+	case FactorOneMinusSrcAlpha:
+		return 1 - src[3]
+	case FactorDstAlpha:
+		return dst[3]
+	case FactorOneMinusDstAlpha:
+		return 1 - dst[3]
+	}
+	return 0
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
+
+func clampInt(x, min, max int) int {
+	if x < min {
+		return min
+	}
+	if x > max {
+		return max
+	}
+	return x
+}
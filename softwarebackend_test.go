@@ -0,0 +1,242 @@
+package sf
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func newSoftwareTarget(w, h int) (*RenderTarget, *image.RGBA) {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	return NewRenderTargetWithBackend(Vector2{float32(w), float32(h)}, NewSoftwareBackend(img)), img
+}
+
+func quad(left, top, w, h float32, c Color) []Vertex {
+	return []Vertex{
+		{Vector2{left, top}, c, Vector2{0, 0}},
+		{Vector2{left, top + h}, c, Vector2{0, h}},
+		{Vector2{left + w, top + h}, c, Vector2{w, h}},
+		{Vector2{left + w, top}, c, Vector2{w, 0}},
+	}
+}
+
+func countPixels(img *image.RGBA, c color.RGBA) int {
+	n := 0
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestSoftwareClear(t *testing.T) {
+	target, img := newSoftwareTarget(4, 3)
+	target.Clear(Color{10, 20, 30, 255})
+	if countPixels(img, color.RGBA{10, 20, 30, 255}) != 12 {
+		t.Fail()
+	}
+}
+
+func TestSoftwareQuads(t *testing.T) {
+	target, img := newSoftwareTarget(10, 10)
+	target.Clear(Color{0, 0, 0, 255})
+
+	// Semi-transparent so that pixels on the quad's diagonal would show up if
+	// they were blended twice
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()}
+	target.Render(quad(2, 3, 4, 5, Color{255, 255, 255, 128}), Quads, states)
+
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			want := color.RGBA{0, 0, 0, 255}
+			if x >= 2 && x < 6 && y >= 3 && y < 8 {
+				want = color.RGBA{128, 128, 128, 255}
+			}
+			if got := img.RGBAAt(x, y); got != want {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestSoftwarePrimitives(t *testing.T) {
+	white := Color{255, 255, 255, 255}
+	tests := []struct {
+		primType PrimitiveType
+		verts    []Vertex
+		want     int
+	}{
+		{Points, []Vertex{{Pos: Vector2{1, 1}, Color: white}, {Pos: Vector2{3, 2}, Color: white}}, 2},
+		{Lines, []Vertex{{Pos: Vector2{0, 0.5}, Color: white}, {Pos: Vector2{5, 0.5}, Color: white}}, 5},
+		{LineStrip, []Vertex{{Pos: Vector2{0, 0.5}, Color: white}, {Pos: Vector2{4, 0.5}, Color: white},
+			{Pos: Vector2{4, 4.5}, Color: white}}, 8},
+		{Triangles, quad(0, 0, 4, 4, white)[:3], 10},
+		{TriangleStrip, []Vertex{{Pos: Vector2{0, 0}, Color: white}, {Pos: Vector2{0, 4}, Color: white},
+			{Pos: Vector2{4, 0}, Color: white}, {Pos: Vector2{4, 4}, Color: white}}, 16},
+		{TriangleFan, quad(0, 0, 4, 4, white), 16},
+		{Quads, append(quad(0, 0, 2, 2, white), quad(4, 4, 2, 3, white)...), 10},
+	}
+
+	for _, test := range tests {
+		target, img := newSoftwareTarget(8, 8)
+		target.Clear(Color{0, 0, 0, 255})
+		target.Render(test.verts, test.primType, RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()})
+		if got := countPixels(img, color.RGBA{255, 255, 255, 255}); got != test.want {
+			t.Errorf("primitive type %d: %d pixels drawn, want %d", test.primType, got, test.want)
+		}
+	}
+}
+
+func TestSoftwareColorInterpolation(t *testing.T) {
+	target, img := newSoftwareTarget(4, 1)
+	target.Clear(Color{0, 0, 0, 255})
+
+	verts := quad(0, 0, 4, 1, Color{0, 0, 0, 255})
+	verts[2].Color = Color{255, 0, 0, 255}
+	verts[3].Color = Color{255, 0, 0, 255}
+	target.Render(verts, Quads, RenderStates{BlendMode: BlendNone(), Transform: IdentityTransform()})
+
+	// Sampled at the pixel centers: 1/8, 3/8, 5/8 and 7/8 of the way
+	for x, want := range []uint8{32, 96, 159, 223} {
+		if got := img.RGBAAt(x, 0).R; got != want {
+			t.Errorf("pixel %d: red = %d, want %d", x, got, want)
+		}
+	}
+}
+
+func TestSoftwareTexture(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{0, 255, 0, 255})
+	src.SetNRGBA(0, 1, color.NRGBA{0, 0, 255, 255})
+	src.SetNRGBA(1, 1, color.NRGBA{255, 255, 255, 255})
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, img := newSoftwareTarget(4, 4)
+	target.Clear(Color{0, 0, 0, 255})
+
+	// Magnify the texture twice, tinted with half intensity
+	verts := quad(0, 0, 4, 4, Color{128, 128, 128, 255})
+	for i := range verts {
+		verts[i].TexCoords = verts[i].TexCoords.Div(2)
+	}
+	target.Render(verts, Quads, RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex})
+
+	checks := map[image.Point]color.RGBA{
+		{0, 0}: {128, 0, 0, 255},
+		{3, 1}: {0, 128, 0, 255},
+		{1, 3}: {0, 0, 128, 255},
+		{2, 2}: {128, 128, 128, 255},
+	}
+	for p, want := range checks {
+		if got := img.RGBAAt(p.X, p.Y); got != want {
+			t.Errorf("pixel %v = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestSoftwareBlendModes(t *testing.T) {
+	tests := []struct {
+		mode BlendMode
+		want color.RGBA
+	}{
+		{BlendAlpha(), color.RGBA{150, 50, 125, 255}},
+		{BlendAdd(), color.RGBA{200, 100, 225, 255}},
+		{BlendMultiply(), color.RGBA{78, 0, 39, 128}},
+		{BlendNone(), color.RGBA{200, 0, 50, 128}},
+		{BlendMode{}, color.RGBA{150, 50, 125, 255}},
+		{NewBlendMode(FactorOne, FactorOne, EquationSubtract), color.RGBA{100, 0, 0, 0}},
+		{NewBlendMode(FactorOne, FactorOne, EquationReverseSubtract), color.RGBA{0, 100, 150, 127}},
+		{NewBlendMode(FactorOne, FactorOne, EquationMin), color.RGBA{100, 0, 50, 128}},
+		{NewBlendMode(FactorOne, FactorOne, EquationMax), color.RGBA{200, 100, 200, 255}},
+
+		// Erase: keep the color, make transparent where drawn
+		{BlendMode{FactorZero, FactorOne, EquationAdd, FactorZero, FactorOneMinusSrcAlpha, EquationAdd},
+			color.RGBA{100, 100, 200, 127}},
+	}
+
+	for _, test := range tests {
+		target, img := newSoftwareTarget(1, 1)
+		target.Clear(Color{100, 100, 200, 255})
+		target.Render(quad(0, 0, 1, 1, Color{200, 0, 50, 128}), Quads,
+			RenderStates{BlendMode: test.mode, Transform: IdentityTransform()})
+		if got := img.RGBAAt(0, 0); got != test.want {
+			t.Errorf("blend mode %v: got %v, want %v", test.mode, got, test.want)
+		}
+	}
+}
+
+func TestSoftwareView(t *testing.T) {
+	target, img := newSoftwareTarget(8, 8)
+	target.Clear(Color{0, 0, 0, 255})
+
+	// Zoomed-in view drawn in the bottom-right quarter of the target
+	view := NewView()
+	view.Reset(Rect{0, 0, 2, 2})
+	view.SetViewport(Rect{0.5, 0.5, 0.5, 0.5})
+	target.SetView(*view)
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads,
+		RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform()})
+
+	if countPixels(img, color.RGBA{255, 255, 255, 255}) != 4 {
+		t.Fail()
+	}
+	for _, p := range []image.Point{{4, 4}, {5, 5}} {
+		if img.RGBAAt(p.X, p.Y) != (color.RGBA{255, 255, 255, 255}) {
+			t.Errorf("pixel %v not drawn", p)
+		}
+	}
+}
+
+func TestSoftwareTextureSampling(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{255, 255, 255, 255})
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		smooth, repeated bool
+		texWidth         float32
+		want             []uint8
+	}{
+		{false, false, 2, []uint8{0, 0, 255, 255}},
+		{false, false, 4, []uint8{0, 255, 255, 255}},
+		{false, true, 4, []uint8{0, 255, 0, 255}},
+		{true, false, 2, []uint8{0, 64, 191, 255}},
+	}
+
+	for _, test := range tests {
+		id := tex.cacheId
+		tex.SetSmooth(test.smooth)
+		tex.SetRepeated(test.repeated)
+		if tex.IsSmooth() != test.smooth || tex.IsRepeated() != test.repeated {
+			t.Fatal("settings not stored")
+		}
+		if (test.smooth || test.repeated) && tex.cacheId == id {
+			t.Error("cache id not changed with the settings")
+		}
+
+		// Texture coordinates spanning texWidth pixels over 4 pixels
+		target, img := newSoftwareTarget(4, 1)
+		verts := quad(0, 0, 4, 1, Color{255, 255, 255, 255})
+		for i := range verts {
+			verts[i].TexCoords = Vector2{verts[i].TexCoords.X / 4 * test.texWidth, 0.5}
+		}
+		target.Render(verts, Quads, RenderStates{BlendMode: BlendNone(), Transform: IdentityTransform(), Texture: tex})
+
+		for x, want := range test.want {
+			if got := img.RGBAAt(x, 0).R; got != want {
+				t.Errorf("smooth %v, repeated %v: pixel %d = %d, want %d", test.smooth, test.repeated, x, got, want)
+			}
+		}
+	}
+}
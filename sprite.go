@@ -1,24 +1,29 @@
 package sf
 
+import (
+	"math"
+)
+
+// Sprite draws a texture, or a part of it, with its own position, rotation,
+// scale and origin
 type Sprite struct {
+	Transformable
 	texture *Texture
 	rect    Rect
 	verts   [4]Vertex
-	//T       *Transformable // TODO? This is a workaround since we can't inherit.
 }
 
 func NewSprite(t *Texture) *Sprite {
-	spr := &Sprite{}
+	spr := &Sprite{Transformable: *NewTransformable()}
 	spr.SetTexture(t)
 	spr.SetColor(Color{255, 255, 255, 255})
-	//spr.T = NewTransformable()
 
 	return spr
 }
 
 func (s *Sprite) Render(t *RenderTarget, states RenderStates) {
 	states.Texture = s.texture
-	//states.Transform.Combine(s.T.Transform())
+	states.Transform.Combine(s.Transform())
 	t.Render(s.verts[:], Quads, states)
 }
 
@@ -46,15 +51,35 @@ func (s *Sprite) Texture() *Texture {
 	return s.texture
 }
 
-func (s *Sprite) LocalBounds() Rect {
+// TextureRect returns the part of the texture drawn by the sprite
+func (s *Sprite) TextureRect() Rect {
 	return s.rect
 }
 
+// LocalBounds returns the bounding rectangle of the sprite, ignoring its
+// transform. Its origin is always (0, 0), like Shape and Text bounds.
+//
+// Before Sprite was Transformable, LocalBounds returned the texture rect:
+// callers expecting that must use TextureRect instead.
+func (s *Sprite) LocalBounds() Rect {
+	w := float32(math.Abs(float64(s.rect.W)))
+	h := float32(math.Abs(float64(s.rect.H)))
+	return Rect{0, 0, w, h}
+}
+
+// GlobalBounds returns the bounding rectangle of the sprite, once transformed
+func (s *Sprite) GlobalBounds() Rect {
+	transform := s.Transform()
+	return transform.TransformRect(s.LocalBounds())
+}
+
 func (s *Sprite) updatePositions() {
+	bounds := s.LocalBounds()
+
 	s.verts[0].Pos = Vector2{}
-	s.verts[1].Pos = Vector2{0, s.rect.H}
-	s.verts[2].Pos = Vector2{s.rect.W, s.rect.H}
-	s.verts[3].Pos = Vector2{s.rect.W, 0}
+	s.verts[1].Pos = Vector2{0, bounds.H}
+	s.verts[2].Pos = Vector2{bounds.W, bounds.H}
+	s.verts[3].Pos = Vector2{bounds.W, 0}
 }
 
 func (s *Sprite) updateTexCoords() {
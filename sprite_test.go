@@ -0,0 +1,69 @@
+package sf
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSpriteBounds(t *testing.T) {
+	tex, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 64, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spr := NewSprite(tex)
+	spr.SetTextureRect(Rect{16, 0, 20, 10})
+	spr.SetOriginXY(10, 5)
+	spr.SetPositionXY(100, 50)
+	spr.SetScaleXY(2, 3)
+
+	if spr.LocalBounds() != (Rect{0, 0, 20, 10}) {
+		t.Errorf("local bounds: %v", spr.LocalBounds())
+	}
+	if spr.GlobalBounds() != (Rect{80, 35, 40, 30}) {
+		t.Errorf("global bounds: %v", spr.GlobalBounds())
+	}
+
+	spr.SetRotation(90)
+	bounds := spr.GlobalBounds()
+	if !approxRect(bounds, Rect{85, 30, 30, 40}) {
+		t.Errorf("rotated global bounds: %v", bounds)
+	}
+}
+
+func approxRect(r, r2 Rect) bool {
+	near := func(a, b float32) bool { return a-b < 1e-3 && b-a < 1e-3 }
+	return near(r.Left, r2.Left) && near(r.Top, r2.Top) && near(r.W, r2.W) && near(r.H, r2.H)
+}
+
+func TestSpriteDraw(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := range src.Pix {
+		src.Pix[i] = 255
+	}
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	target := NewRenderTargetWithBackend(Vector2{8, 8}, NewSoftwareBackend(img))
+	target.Clear(Color{0, 0, 0, 255})
+
+	// Drawn through the Drawable interface, with default states
+	spr := NewSprite(tex)
+	spr.SetPositionXY(3, 2)
+	spr.SetScaleXY(1, 0.5)
+	var d Drawable = spr
+	target.Draw(d)
+
+	bounds := image.Rect(3, 2, 7, 4)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			drawn := img.RGBAAt(x, y).R == 255
+			if drawn != image.Pt(x, y).In(bounds) {
+				t.Fatalf("pixel (%d, %d) drawn: %v", x, y, drawn)
+			}
+		}
+	}
+}
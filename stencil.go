@@ -0,0 +1,73 @@
+package sf
+
+// StencilMode tells how a draw uses the stencil mask of its target. The mask
+// restricts drawing to arbitrary shapes: circles, polygons, or the opaque
+// pixels of a texture.
+type StencilMode uint8
+
+const (
+	StencilNone    StencilMode = iota // The mask is ignored. In RenderStates, the target's mask mode is used.
+	StencilWrite                      // The drawn pixels are added to the mask, the target isn't drawn into
+	StencilInside                     // Only the pixels inside the mask are drawn
+	StencilOutside                    // Only the pixels outside the mask are drawn
+	StencilIgnore                     // The mask is ignored, whatever the target's mask mode
+)
+
+// DrawMask adds the pixels covered by a drawable to the stencil mask, without
+// drawing it. Fully transparent pixels aren't added, so that sprites can be
+// used as alpha masks.
+func (r *RenderTarget) DrawMask(d Drawable, states ...RenderStates) {
+	s := DefaultRenderStates()
+	if len(states) > 0 {
+		s = states[0]
+	}
+	s.Stencil = StencilWrite
+	d.Render(r, s)
+}
+
+// SetMaskMode sets how the following draws use the stencil mask, unless their
+// states set a stencil mode. The default is StencilNone.
+func (r *RenderTarget) SetMaskMode(mode StencilMode) {
+	if mode == StencilIgnore {
+		mode = StencilNone
+	}
+	r.maskMode = mode
+}
+
+func (r *RenderTarget) MaskMode() StencilMode {
+	return r.maskMode
+}
+
+// ClearMask empties the stencil mask
+func (r *RenderTarget) ClearMask() {
+	r.Flush()
+	if r.activate() != nil {
+		return
+	}
+	r.prepareClear()
+	r.backend.ClearStencil()
+}
+
+// ResetMask empties the stencil mask and stops restricting the following
+// draws to it
+func (r *RenderTarget) ResetMask() {
+	r.ClearMask()
+	r.maskMode = StencilNone
+}
+
+// resolveStencil returns how a draw uses the mask, given the stencil mode of
+// its states
+func (r *RenderTarget) resolveStencil(mode StencilMode) StencilMode {
+	switch mode {
+	case StencilNone:
+		return r.maskMode
+	case StencilIgnore:
+		return StencilNone
+	}
+	return mode
+}
+
+func (r *RenderTarget) applyStencilMode(mode StencilMode) {
+	r.backend.SetStencilMode(mode)
+	r.lastStencilMode = mode
+}
@@ -0,0 +1,107 @@
+package sf
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestStencil(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	full := quad(0, 0, 16, 16, Color{255, 255, 255, 255})
+	circle := NewCircleShape(5, 30)
+	circle.SetPosition(Vector2{3, 3})
+
+	// Pixels covered by the circle when drawn normally
+	target, img := newSoftwareTarget(16, 16)
+	target.Clear(Color{0, 0, 0, 255})
+	target.Draw(circle)
+	covered := countPixels(img, white)
+	if covered == 0 || covered == 256 {
+		t.Fatalf("circle covers %d pixels", covered)
+	}
+
+	// Drawing the mask doesn't draw the circle
+	target.Clear(Color{0, 0, 0, 255})
+	target.DrawMask(circle)
+	if got := countPixels(img, white); got != 0 {
+		t.Errorf("mask drawn into the target: %d pixels", got)
+	}
+
+	target.SetMaskMode(StencilInside)
+	target.Render(full, Quads, DefaultRenderStates())
+	if got := countPixels(img, white); got != covered {
+		t.Errorf("inside: %d pixels drawn, want %d", got, covered)
+	}
+
+	// The states override the mask mode
+	target.Clear(Color{0, 0, 0, 255})
+	states := DefaultRenderStates()
+	states.Stencil = StencilOutside
+	target.Render(full, Quads, states)
+	if got := countPixels(img, white); got != 256-covered {
+		t.Errorf("outside: %d pixels drawn, want %d", got, 256-covered)
+	}
+
+	// A draw can ignore the mask, e.g. a HUD over a masked scene
+	target.Clear(Color{0, 0, 0, 255})
+	states.Stencil = StencilIgnore
+	target.Render(full, Quads, states)
+	if got := countPixels(img, white); got != 256 {
+		t.Errorf("ignored mask: %d pixels drawn", got)
+	}
+
+	// An empty mask lets nothing through
+	target.Clear(Color{0, 0, 0, 255})
+	target.ClearMask()
+	target.Render(full, Quads, DefaultRenderStates())
+	if got := countPixels(img, white); got != 0 {
+		t.Errorf("cleared mask: %d pixels drawn", got)
+	}
+
+	target.DrawMask(circle)
+	target.ResetMask()
+	if target.MaskMode() != StencilNone {
+		t.Errorf("mask mode not reset: %v", target.MaskMode())
+	}
+	target.Render(full, Quads, DefaultRenderStates())
+	if got := countPixels(img, white); got != 256 {
+		t.Errorf("reset mask: %d pixels drawn", got)
+	}
+}
+
+func TestStencilAlphaMask(t *testing.T) {
+	// Left half opaque, right half transparent
+	mask := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 2; x++ {
+			mask.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+		}
+	}
+	tex, err := CreateTexture(mask)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, img := newSoftwareTarget(4, 4)
+	target.SetBatching(true)
+	target.Clear(Color{0, 0, 0, 255})
+	target.DrawMask(NewSprite(tex))
+
+	states := DefaultRenderStates()
+	states.Stencil = StencilInside
+	target.Render(quad(0, 0, 4, 4, Color{255, 0, 0, 255}), Quads, states)
+	states.Stencil = StencilOutside
+	target.Render(quad(0, 0, 4, 4, Color{0, 255, 0, 255}), Quads, states)
+	target.Flush()
+
+	if stats := target.Stats(); stats.Batches != 3 {
+		t.Errorf("stencil modes merged into the same batch: %+v", stats)
+	}
+	if img.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || img.RGBAAt(3, 3) != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("got %v inside and %v outside", img.RGBAAt(0, 0), img.RGBAAt(3, 3))
+	}
+	if countPixels(img, color.RGBA{255, 0, 0, 255}) != 8 || countPixels(img, color.RGBA{0, 255, 0, 255}) != 8 {
+		t.Error("alpha mask not applied")
+	}
+}
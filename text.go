@@ -0,0 +1,371 @@
+package sf
+
+import (
+	"math"
+)
+
+// TextStyle is a combination of flags changing the appearance of a Text
+type TextStyle uint8
+
+const (
+	TextRegular       TextStyle = 0               // Regular characters, no style
+	TextBold          TextStyle = 1 << (iota - 1) // Bold characters
+	TextItalic                                    // Italic characters
+	TextUnderlined                                // Underlined characters
+	TextStrikeThrough                             // Strike through characters
+)
+
+// Shear of italic characters, 12 degrees
+const italicShear = 0.209
+
+// Text is a string drawn with a Font
+type Text struct {
+	Transformable
+	str                 []rune    // String to display
+	font                *Font     // Font used to display the string
+	characterSize       uint      // Base size of characters, in pixels
+	letterSpacingFactor float32   // Spacing factor between letters
+	lineSpacingFactor   float32   // Spacing factor between lines
+	style               TextStyle // Text style (see TextStyle enum)
+	fillColor           Color     // Text fill color
+	vertices            []Vertex  // Triangles of the glyphs and lines
+	bounds              Rect      // Bounding rectangle of the text (in local coordinates)
+	geometryNeedUpdate  bool      // Does the geometry need to be recomputed?
+}
+
+// NewText creates a text. The font must stay alive as long as the text uses it.
+func NewText(str string, font *Font, characterSize uint) *Text {
+	return &Text{
+		Transformable:       *NewTransformable(),
+		str:                 []rune(str),
+		font:                font,
+		characterSize:       characterSize,
+		letterSpacingFactor: 1,
+		lineSpacingFactor:   1,
+		fillColor:           Color{255, 255, 255, 255},
+		geometryNeedUpdate:  true,
+	}
+}
+
+// SetString sets the UTF-8 string to display
+func (t *Text) SetString(str string) {
+	t.str = []rune(str)
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) String() string {
+	return string(t.str)
+}
+
+func (t *Text) SetFont(font *Font) {
+	t.font = font
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) Font() *Font {
+	return t.font
+}
+
+// SetCharacterSize sets the base size of the characters, in pixels
+func (t *Text) SetCharacterSize(size uint) {
+	t.characterSize = size
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) CharacterSize() uint {
+	return t.characterSize
+}
+
+// SetLetterSpacing sets the factor of the default spacing between letters.
+// 1 is the font's spacing, more spreads the letters out.
+func (t *Text) SetLetterSpacing(spacingFactor float32) {
+	t.letterSpacingFactor = spacingFactor
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) LetterSpacing() float32 {
+	return t.letterSpacingFactor
+}
+
+// SetLineSpacing sets the factor of the font's spacing between lines
+func (t *Text) SetLineSpacing(spacingFactor float32) {
+	t.lineSpacingFactor = spacingFactor
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) LineSpacing() float32 {
+	return t.lineSpacingFactor
+}
+
+// SetStyle sets the style of the text, a combination of TextStyle flags
+func (t *Text) SetStyle(style TextStyle) {
+	t.style = style
+	t.geometryNeedUpdate = true
+}
+
+func (t *Text) Style() TextStyle {
+	return t.style
+}
+
+func (t *Text) SetFillColor(color Color) {
+	t.fillColor = color
+	for i := range t.vertices {
+		t.vertices[i].Color = color
+	}
+}
+
+func (t *Text) FillColor() Color {
+	return t.fillColor
+}
+
+// FindCharacterPos returns the position of the index-th character, in global
+// coordinates. The index is in characters, not bytes. An index past the end
+// returns the position after the last character, a negative one the position
+// of the first character.
+func (t *Text) FindCharacterPos(index int) Vector2 {
+	if t.font == nil {
+		return Vector2{}
+	}
+
+	if index > len(t.str) {
+		index = len(t.str)
+	} else if index < 0 {
+		index = 0
+	}
+
+	// Precompute the variables needed by the algorithm
+	isBold := t.style&TextBold != 0
+	letterSpacing := t.letterSpacing()
+	whitespaceWidth := t.font.Glyph(' ', t.characterSize, isBold).Advance + letterSpacing
+	lineSpacing := t.font.LineSpacing(t.characterSize) * t.lineSpacingFactor
+
+	// Compute the position
+	var position Vector2
+	var prevChar rune
+	for _, curChar := range t.str[:index] {
+		if curChar == '\r' {
+			continue
+		}
+
+		// Apply the kerning offset
+		position.X += t.font.Kerning(prevChar, curChar, t.characterSize)
+		prevChar = curChar
+
+		// Handle special characters
+		switch curChar {
+		case ' ':
+			position.X += whitespaceWidth
+			continue
+		case '\t':
+			position.X += whitespaceWidth * 4
+			continue
+		case '\n':
+			position.Y += lineSpacing
+			position.X = 0
+			continue
+		}
+
+		// For regular characters, add the advance offset of the glyph
+		position.X += t.font.Glyph(curChar, t.characterSize, isBold).Advance + letterSpacing
+	}
+
+	// Transform the position to global coordinates
+	transform := t.Transform()
+	return transform.TransformPoint(position)
+}
+
+// LocalBounds returns the bounding rectangle of the text, ignoring its
+// transform
+func (t *Text) LocalBounds() Rect {
+	t.ensureGeometryUpdate()
+	return t.bounds
+}
+
+// GlobalBounds returns the bounding rectangle of the text once transformed
+func (t *Text) GlobalBounds() Rect {
+	transform := t.Transform()
+	return transform.TransformRect(t.LocalBounds())
+}
+
+func (t *Text) Render(target *RenderTarget, states RenderStates) {
+	if t.font == nil {
+		return
+	}
+
+	t.ensureGeometryUpdate()
+
+	states.Transform.Combine(t.Transform())
+	states.Texture = t.font.Texture(t.characterSize)
+	target.Render(t.vertices, Triangles, states)
+}
+
+// letterSpacing returns the space added between letters
+func (t *Text) letterSpacing() float32 {
+	whitespaceWidth := t.font.Glyph(' ', t.characterSize, t.style&TextBold != 0).Advance
+	return (whitespaceWidth / 3) * (t.letterSpacingFactor - 1)
+}
+
+// ensureGeometryUpdate lays out the string again if anything changed
+func (t *Text) ensureGeometryUpdate() {
+	if !t.geometryNeedUpdate {
+		return
+	}
+	t.geometryNeedUpdate = false
+
+	// Clear the previous geometry
+	t.vertices = t.vertices[:0]
+	t.bounds = Rect{}
+
+	// No font or text: nothing to draw
+	if t.font == nil || len(t.str) == 0 {
+		return
+	}
+
+	// Compute values related to the text style
+	isBold := t.style&TextBold != 0
+	isUnderlined := t.style&TextUnderlined != 0
+	isStrikeThrough := t.style&TextStrikeThrough != 0
+	var shear float32
+	if t.style&TextItalic != 0 {
+		shear = italicShear
+	}
+
+	underlineOffset := t.font.UnderlinePosition(t.characterSize)
+	underlineThickness := t.font.UnderlineThickness(t.characterSize)
+
+	// Compute the location of the strike through dynamically, from the
+	// middle of the letter "x"
+	xBounds := t.font.Glyph('x', t.characterSize, isBold).Bounds
+	strikeThroughOffset := xBounds.Top + xBounds.H/2
+
+	// Precompute the variables needed by the algorithm
+	letterSpacing := t.letterSpacing()
+	whitespaceWidth := t.font.Glyph(' ', t.characterSize, isBold).Advance + letterSpacing
+	lineSpacing := t.font.LineSpacing(t.characterSize) * t.lineSpacingFactor
+	x := float32(0)
+	y := float32(t.characterSize)
+
+	// Create one quad for each character
+	minX, minY := float32(t.characterSize), float32(t.characterSize)
+	maxX, maxY := float32(0), float32(0)
+	var prevChar rune
+	for _, curChar := range t.str {
+		// Skip the \r char to avoid weird graphical issues
+		if curChar == '\r' {
+			continue
+		}
+
+		// Apply the kerning offset
+		x += t.font.Kerning(prevChar, curChar, t.characterSize)
+
+		// If we're using the underlined style and there's a new line, draw a line
+		if isUnderlined && curChar == '\n' && prevChar != '\n' {
+			t.addLine(x, y, underlineOffset, underlineThickness)
+		}
+
+		// If we're using the strike through style and there's a new line, draw a line across all characters
+		if isStrikeThrough && curChar == '\n' && prevChar != '\n' {
+			t.addLine(x, y, strikeThroughOffset, underlineThickness)
+		}
+
+		prevChar = curChar
+
+		// Handle special characters
+		if curChar == ' ' || curChar == '\n' || curChar == '\t' {
+			// Update the current bounds (min coordinates)
+			minX = min32(minX, x)
+			minY = min32(minY, y)
+
+			switch curChar {
+			case ' ':
+				x += whitespaceWidth
+			case '\t':
+				x += whitespaceWidth * 4
+			case '\n':
+				y += lineSpacing
+				x = 0
+			}
+
+			// Update the current bounds (max coordinates)
+			maxX = max32(maxX, x)
+			maxY = max32(maxY, y)
+
+			// Next glyph, no need to create a quad for whitespace
+			continue
+		}
+
+		// Extract the current glyph's description
+		glyph := t.font.Glyph(curChar, t.characterSize, isBold)
+
+		// Add the glyph to the vertices
+		t.addGlyphQuad(Vector2{x, y}, glyph, shear)
+
+		// Update the current bounds
+		left := glyph.Bounds.Left
+		top := glyph.Bounds.Top
+		right := glyph.Bounds.Left + glyph.Bounds.W
+		bottom := glyph.Bounds.Top + glyph.Bounds.H
+
+		minX = min32(minX, x+left-shear*bottom)
+		maxX = max32(maxX, x+right-shear*top)
+		minY = min32(minY, y+top)
+		maxY = max32(maxY, y+bottom)
+
+		// Advance to the next character
+		x += glyph.Advance + letterSpacing
+	}
+
+	// If we're using the underlined style, add the last line
+	if isUnderlined && x > 0 {
+		t.addLine(x, y, underlineOffset, underlineThickness)
+	}
+
+	// If we're using the strike through style, add the last line across all characters
+	if isStrikeThrough && x > 0 {
+		t.addLine(x, y, strikeThroughOffset, underlineThickness)
+	}
+
+	// Update the bounding rectangle
+	t.bounds = Rect{minX, minY, maxX - minX, maxY - minY}
+}
+
+// addLine adds an underline or strike through line of the given length
+func (t *Text) addLine(lineLength, lineTop, offset, thickness float32) {
+	top := float32(math.Floor(float64(lineTop + offset - thickness/2 + 0.5)))
+	bottom := top + float32(math.Floor(float64(thickness+0.5)))
+
+	// Textured with the white square reserved by the font
+	texCoords := Vector2{1, 1}
+	t.vertices = append(t.vertices,
+		Vertex{Vector2{0, top}, t.fillColor, texCoords},
+		Vertex{Vector2{lineLength, top}, t.fillColor, texCoords},
+		Vertex{Vector2{0, bottom}, t.fillColor, texCoords},
+		Vertex{Vector2{0, bottom}, t.fillColor, texCoords},
+		Vertex{Vector2{lineLength, top}, t.fillColor, texCoords},
+		Vertex{Vector2{lineLength, bottom}, t.fillColor, texCoords})
+}
+
+// addGlyphQuad adds the two triangles of a glyph, sheared for italics
+func (t *Text) addGlyphQuad(position Vector2, glyph Glyph, shear float32) {
+	// Include the transparent pixel around the glyph, for smooth edges
+	const padding = 1
+
+	left := glyph.Bounds.Left - padding
+	top := glyph.Bounds.Top - padding
+	right := glyph.Bounds.Left + glyph.Bounds.W + padding
+	bottom := glyph.Bounds.Top + glyph.Bounds.H + padding
+
+	u1 := glyph.TextureRect.Left - padding
+	v1 := glyph.TextureRect.Top - padding
+	u2 := glyph.TextureRect.Left + glyph.TextureRect.W + padding
+	v2 := glyph.TextureRect.Top + glyph.TextureRect.H + padding
+
+	x, y := position.X, position.Y
+	t.vertices = append(t.vertices,
+		Vertex{Vector2{x + left - shear*top, y + top}, t.fillColor, Vector2{u1, v1}},
+		Vertex{Vector2{x + right - shear*top, y + top}, t.fillColor, Vector2{u2, v1}},
+		Vertex{Vector2{x + left - shear*bottom, y + bottom}, t.fillColor, Vector2{u1, v2}},
+		Vertex{Vector2{x + left - shear*bottom, y + bottom}, t.fillColor, Vector2{u1, v2}},
+		Vertex{Vector2{x + right - shear*top, y + top}, t.fillColor, Vector2{u2, v1}},
+		Vertex{Vector2{x + right - shear*bottom, y + bottom}, t.fillColor, Vector2{u2, v2}})
+}
@@ -0,0 +1,102 @@
+package sf
+
+import (
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestTextLayout(t *testing.T) {
+	f, err := NewFontFromMemory(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := NewText("Hello", f, 20)
+	bounds := text.LocalBounds()
+	if bounds.W <= 0 || bounds.H <= 0 || bounds.H > 20 {
+		t.Errorf("bounds: %v", bounds)
+	}
+
+	// Newlines stack lines
+	text.SetString("Hello\nHello")
+	if b := text.LocalBounds(); b.W != bounds.W || b.H <= bounds.H+f.LineSpacing(20)-1 {
+		t.Errorf("two lines bounds: %v", b)
+	}
+
+	// Positions of the characters
+	text.SetPositionXY(100, 50)
+	if p := text.FindCharacterPos(0); p != (Vector2{100, 50}) {
+		t.Errorf("position of the first character: %v", p)
+	}
+	if p := text.FindCharacterPos(-1); p != (Vector2{100, 50}) {
+		t.Errorf("position of a negative index: %v", p)
+	}
+	if p := text.FindCharacterPos(6); p != (Vector2{100, 50 + f.LineSpacing(20)}) {
+		t.Errorf("position after the newline: %v", p)
+	}
+	if p, end := text.FindCharacterPos(8), text.FindCharacterPos(100); p.X <= 100 || end.X <= p.X {
+		t.Errorf("positions on the second line: %v, %v", p, end)
+	}
+
+	text.SetString("a\tb")
+	space := f.Glyph(' ', 20, false).Advance
+	a := f.Glyph('a', 20, false).Advance
+	if p := text.FindCharacterPos(2); p.X-100 != a+f.Kerning('a', '\t', 20)+4*space {
+		t.Errorf("tab: %v", p)
+	}
+
+	// Letter spacing spreads the characters
+	text.SetString("abc")
+	end := text.FindCharacterPos(3)
+	text.SetLetterSpacing(2)
+	if spread := text.FindCharacterPos(3); spread.X <= end.X {
+		t.Errorf("letter spacing: %v, %v", end, spread)
+	}
+
+	// Styles add lines and slant the glyphs
+	text.SetLetterSpacing(1)
+	regular := text.LocalBounds()
+	text.SetStyle(TextUnderlined | TextStrikeThrough)
+	text.ensureGeometryUpdate()
+	if len(text.vertices) != 6*5 {
+		t.Errorf("%d vertices with lines", len(text.vertices))
+	}
+	text.SetStyle(TextItalic)
+	if italic := text.LocalBounds(); italic.W <= regular.W {
+		t.Errorf("italic bounds: %v, regular: %v", italic, regular)
+	}
+}
+
+func TestTextDraw(t *testing.T) {
+	f, err := NewFontFromMemory(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, img := newSoftwareTarget(64, 32)
+	target.Clear(Color{0, 0, 0, 255})
+
+	text := NewText("Go_", f, 16)
+	text.SetPositionXY(2, 2)
+	text.SetFillColor(Color{255, 0, 0, 255})
+	text.SetStyle(TextUnderlined)
+	target.Draw(text)
+
+	// Fully covered pixels, at least the underline's
+	if n := countPixels(img, color.RGBA{255, 0, 0, 255}); n < int(text.LocalBounds().W) {
+		t.Errorf("%d pixels drawn", n)
+	}
+
+	// Nothing outside of the bounds
+	b := text.GlobalBounds()
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			in := float32(x)+1 > b.Left && float32(x) < b.Left+b.W && float32(y)+1 > b.Top && float32(y) < b.Top+b.H
+			if !in && img.RGBAAt(x, y) != (color.RGBA{0, 0, 0, 255}) {
+				t.Fatalf("pixel (%d, %d) drawn outside of %v", x, y, b)
+			}
+		}
+	}
+}
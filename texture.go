@@ -1,42 +1,122 @@
 package sf
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"github.com/go-gl-legacy/gl"
 	"image"
-	"image/png"
+	"image/draw"
+	"io"
+	"io/fs"
 	"os"
+	"runtime"
+	"sync"
+
+	// Image formats understood by the LoadTexture functions
+	_ "golang.org/x/image/bmp"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 )
 
 type Texture struct {
 	t             gl.Texture
 	size          Vector2
-	isSmooth      bool   // Status of the smooth filter
-	isRepeated    bool   // Is the texture in repeat mode?
-	pixelsFlipped bool   // To work around the inconsistency in Y orientation
-	cacheId       uint64 // Unique number that identifies the texture to the render target's cache
+	pixels        *image.NRGBA    // Pixels of the texture, uploaded to OpenGL on first bind then released
+	keepPixels    bool            // Are the pixels kept after the upload? (e.g. font pages, updated in place)
+	pixelsChanged bool            // Must the pixels be uploaded again on next bind?
+	dirty         image.Rectangle // Part of the pixels to upload again on next bind
+	isSmooth      bool            // Status of the smooth filter
+	isRepeated    bool            // Is the texture in repeat mode?
+	paramsChanged bool            // Must the sampler parameters be applied again on next bind?
+	hasMipmap     bool            // Has the mipmap been generated?
+	wantsMipmap   bool            // Must the mipmap be generated on next bind?
+	destroyed     bool            // Has Destroy been called?
+	hasFinalizer  bool            // Is the OpenGL texture deleted once the texture is garbage collected?
+	pixelsFlipped bool            // To work around the inconsistency in Y orientation
+	cacheId       uint64          // Unique number that identifies the texture to the render target's cache
 }
 
-func NewTextureFromFile(fname string) *Texture {
-	f, err := os.Open(fname)
+// TextureError describes why a texture couldn't be loaded
+type TextureError struct {
+	Path  string // File the texture was loaded from, empty if it wasn't loaded from a file
+	Stage string // Stage that failed: "open", "decode" or "create"
+	Err   error  // Underlying error
+}
+
+func (e *TextureError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("sf: can't %s texture: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("sf: can't %s texture %s: %v", e.Stage, e.Path, e.Err)
+}
+
+func (e *TextureError) Unwrap() error {
+	return e.Err
+}
+
+// LoadTexture loads a texture from a PNG, JPEG, GIF or BMP file. Other formats
+// can be added by registering them with the image package. Errors are of type
+// *TextureError. The decoded pixels stay in memory until the texture is first
+// drawn with OpenGL, see CreateTexture.
+func LoadTexture(path string) (*Texture, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return nil, &TextureError{path, "open", err}
 	}
 	defer f.Close()
 
-	img, err := png.Decode(f)
+	return loadTexture(path, f)
+}
+
+// LoadTextureFromFS loads a texture from a file of a file system, e.g. an
+// embed.FS
+func LoadTextureFromFS(fsys fs.FS, name string) (*Texture, error) {
+	f, err := fsys.Open(name)
 	if err != nil {
-		panic(err)
+		return nil, &TextureError{name, "open", err}
 	}
+	defer f.Close()
 
-	t, err := CreateTexture(img)
+	return loadTexture(name, f)
+}
+
+// LoadTextureFromReader loads a texture from the content of an image file
+func LoadTextureFromReader(r io.Reader) (*Texture, error) {
+	return loadTexture("", r)
+}
+
+// LoadTextureFromMemory loads a texture from the content of an image file
+func LoadTextureFromMemory(data []byte) (*Texture, error) {
+	return loadTexture("", bytes.NewReader(data))
+}
+
+// NewTextureFromFile loads a texture from an image file, and panics if it
+// can't be loaded.
+//
+// Deprecated: use LoadTexture, which returns the error.
+func NewTextureFromFile(fname string) *Texture {
+	t, err := LoadTexture(fname)
 	if err != nil {
 		panic(err)
 	}
-
 	return t
 }
 
+func loadTexture(path string, r io.Reader) (*Texture, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, &TextureError{path, "decode", err}
+	}
+
+	t, err := CreateTexture(img)
+	if err != nil {
+		return nil, &TextureError{path, "create", err}
+	}
+	return t, nil
+}
+
 func (t *Texture) Size() Vector2 {
 	return t.size
 }
@@ -52,10 +132,36 @@ const (
 func (t *Texture) Bind(coordType CoordType) {
 	// ensureGlContext()
 
+	// Upload the pixels if it's the first time the texture is used, or if
+	// they changed since
+	if t != nil && t.pixels != nil {
+		if t.t == 0 || t.pixelsChanged {
+			t.upload()
+		} else if !t.dirty.Empty() {
+			t.uploadDirty()
+		}
+
+		// OpenGL has its own copy, no need to keep ours
+		if !t.keepPixels {
+			t.pixels = nil
+		}
+	}
+
 	if t != nil && t.t != 0 {
 		// Bind the texture
 		t.t.Bind(gl.TEXTURE_2D)
 
+		// Apply the settings changed since the last bind
+		if t.wantsMipmap {
+			gl.GenerateMipmap(gl.TEXTURE_2D)
+			t.hasMipmap = true
+			t.wantsMipmap = false
+			t.paramsChanged = true
+		}
+		if t.paramsChanged {
+			t.applyParams()
+		}
+
 		// Check if we need to define a special texture matrix
 		if coordType == CoordPixels || t.pixelsFlipped {
 			matrix := [16]float32{1, 0, 0, 0,
@@ -96,25 +202,357 @@ func (t *Texture) Bind(coordType CoordType) {
 	}
 }
 
+// upload creates the OpenGL texture from the pixels
+func (t *Texture) upload() {
+	imgW, imgH := t.pixels.Bounds().Dx(), t.pixels.Bounds().Dy()
+
+	if t.t == 0 {
+		t.t = gl.GenTexture()
+	}
+	t.t.Bind(gl.TEXTURE_2D)
+
+	gl.TexImage2D(gl.TEXTURE_2D, 0, 4, imgW, imgH, 0, gl.RGBA, gl.UNSIGNED_BYTE, t.pixels.Pix)
+	t.pixelsChanged = false
+	t.dirty = image.Rectangle{}
+
+	// The mipmap no longer matches the pixels
+	t.hasMipmap = false
+	t.applyParams()
+}
+
+// Update copies an image into the texture, with its top-left corner at (x, y).
+// It must fit inside the texture.
+func (t *Texture) Update(img image.Image, x, y int) error {
+	bounds := img.Bounds()
+	dst := image.Rect(x, y, x+bounds.Dx(), y+bounds.Dy())
+	if !dst.In(image.Rect(0, 0, int(t.size.X), int(t.size.Y))) {
+		return errors.New("sf: texture update out of bounds")
+	}
+	if dst.Empty() {
+		return nil
+	}
+
+	// Draw the pending batches using the texture as it was
+	flushBatches(t)
+
+	// Update the pixels, they're uploaded on next bind
+	if t.pixels != nil {
+		draw.Draw(t.pixels, dst, img, bounds.Min, draw.Src)
+		t.invalidate(dst)
+		return nil
+	}
+
+	// The texture only lives in OpenGL (e.g. a render texture's, or one
+	// already drawn), upload the image right away
+	if t.t == 0 {
+		return errors.New("sf: texture has no pixels")
+	}
+	if err := ensureGlContext(); err != nil {
+		return err
+	}
+
+	pixels := image.NewNRGBA(image.Rect(0, 0, dst.Dx(), dst.Dy()))
+	draw.Draw(pixels, pixels.Rect, img, bounds.Min, draw.Src)
+	if t.pixelsFlipped {
+		flipVertically(pixels)
+		y = int(t.size.Y) - dst.Max.Y
+	}
+
+	t.t.Bind(gl.TEXTURE_2D)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, x, y, dst.Dx(), dst.Dy(), gl.RGBA, gl.UNSIGNED_BYTE, pixels.Pix)
+	if t.hasMipmap {
+		t.hasMipmap = false
+		t.applyParams()
+	}
+	t.cacheId = nextTextureCacheId()
+
+	// We changed the bindings behind the render targets' back
+	invalidateGLStates()
+	return nil
+}
+
+// CopyToImage returns a copy of the pixels of the texture, top row first. The
+// pixels of textures only living in OpenGL (e.g. a render texture's, or one
+// already drawn) are read back from the graphics card.
+func (t *Texture) CopyToImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, int(t.size.X), int(t.size.Y)))
+
+	if t.pixels != nil {
+		draw.Draw(img, img.Rect, t.pixels, t.pixels.Rect.Min, draw.Src)
+		return img
+	}
+
+	if t.t == 0 || img.Rect.Empty() || ensureGlContext() != nil {
+		return img
+	}
+
+	t.t.Bind(gl.TEXTURE_2D)
+	gl.GetTexImage(gl.TEXTURE_2D, 0, gl.RGBA, gl.UNSIGNED_BYTE, img.Pix)
+	if t.pixelsFlipped {
+		flipVertically(img)
+	}
+
+	// We changed the bindings behind the render targets' back
+	invalidateGLStates()
+	return img
+}
+
+// Copy returns a new texture with the same pixels and settings. Like the
+// textures of CreateTexture, the copy keeps its pixels in memory until first
+// drawn, even if t only lives in OpenGL.
+func (t *Texture) Copy() (*Texture, error) {
+	c, err := CreateTexture(t.CopyToImage())
+	if err != nil {
+		return nil, err
+	}
+
+	c.isSmooth = t.isSmooth
+	c.isRepeated = t.isRepeated
+	c.wantsMipmap = t.hasMipmap || t.wantsMipmap
+	return c, nil
+}
+
+// Swap exchanges the contents and settings of two textures. The texture of a
+// RenderTexture must not be swapped.
+func (t *Texture) Swap(other *Texture) {
+	// Draw the pending batches using the textures as they were
+	flushBatches(t)
+	flushBatches(other)
+
+	*t, *other = *other, *t
+
+	// The finalizers are attached to the pointers, make them follow the
+	// OpenGL textures they delete
+	t.setFinalizer(t.hasFinalizer)
+	other.setFinalizer(other.hasFinalizer)
+
+	// Force the render targets to bind the textures again
+	t.cacheId = nextTextureCacheId()
+	other.cacheId = nextTextureCacheId()
+}
+
+// Destroy deletes the OpenGL texture and the pixels right away, instead of
+// waiting for the garbage collector. The texture must not be used afterwards:
+// render targets draw it as if no texture was set.
+//
+// The texture of a RenderTexture must not be destroyed, RenderTexture.Destroy
+// must be used instead.
+func (t *Texture) Destroy() {
+	flushBatches(t)
+	if t.t != 0 && ensureGlContext() == nil {
+		t.t.Delete()
+
+		// The texture may have been bound behind the render targets' back
+		invalidateGLStates()
+	}
+
+	*t = Texture{destroyed: true}
+	t.setFinalizer(false)
+}
+
+// SetSmooth enables or disables the smooth filter. When enabled, the pixels
+// are interpolated when the texture is scaled, instead of looking blocky.
+func (t *Texture) SetSmooth(smooth bool) {
+	if smooth != t.isSmooth {
+		flushBatches(t)
+		t.isSmooth = smooth
+		t.paramsChanged = true
+		t.cacheId = nextTextureCacheId()
+	}
+}
+
+func (t *Texture) IsSmooth() bool {
+	return t.isSmooth
+}
+
+// SetRepeated enables or disables the repeat mode. When enabled, texture
+// coordinates outside the texture wrap around, e.g. to tile a background.
+// Otherwise the texture's edges are stretched.
+func (t *Texture) SetRepeated(repeated bool) {
+	if repeated != t.isRepeated {
+		flushBatches(t)
+		t.isRepeated = repeated
+		t.paramsChanged = true
+		t.cacheId = nextTextureCacheId()
+	}
+}
+
+func (t *Texture) IsRepeated() bool {
+	return t.isRepeated
+}
+
+// GenerateMipmap generates the mipmap of the texture on next bind, so that it
+// looks better when drawn smaller. It must be generated again after the
+// pixels change.
+func (t *Texture) GenerateMipmap() {
+	flushBatches(t)
+	t.wantsMipmap = true
+	t.cacheId = nextTextureCacheId()
+}
+
+// applyParams sets the sampler parameters of the bound texture
+func (t *Texture) applyParams() {
+	filter := gl.NEAREST
+	if t.isSmooth {
+		filter = gl.LINEAR
+	}
+
+	minFilter := filter
+	if t.hasMipmap {
+		if t.isSmooth {
+			minFilter = gl.LINEAR_MIPMAP_LINEAR
+		} else {
+			minFilter = gl.NEAREST_MIPMAP_LINEAR
+		}
+	}
+
+	wrap := gl.CLAMP_TO_EDGE
+	if t.isRepeated {
+		wrap = gl.REPEAT
+	}
+
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, filter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, wrap)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, wrap)
+	t.paramsChanged = false
+}
+
+// uploadDirty uploads the pixels updated since the last upload
+func (t *Texture) uploadDirty() {
+	// Upload whole rows, they're contiguous in memory
+	r := t.dirty
+	stride := t.pixels.Stride
+	t.t.Bind(gl.TEXTURE_2D)
+	gl.TexSubImage2D(gl.TEXTURE_2D, 0, 0, r.Min.Y, t.pixels.Rect.Dx(), r.Dy(), gl.RGBA, gl.UNSIGNED_BYTE,
+		t.pixels.Pix[r.Min.Y*stride:r.Max.Y*stride])
+	t.dirty = image.Rectangle{}
+
+	// The mipmap no longer matches the pixels
+	if t.hasMipmap {
+		t.hasMipmap = false
+		t.applyParams()
+	}
+}
+
+// invalidate marks a part of the pixels as modified, to be uploaded again on
+// next bind
+func (t *Texture) invalidate(r image.Rectangle) {
+	flushBatches(t)
+	t.dirty = t.dirty.Union(r)
+
+	// Force the render targets to bind the texture again
+	t.cacheId = nextTextureCacheId()
+}
+
+// setPixels replaces the pixels of the texture, they're uploaded again on next
+// bind. The pixels may be modified in place before calling it.
+func (t *Texture) setPixels(img *image.NRGBA) {
+	flushBatches(t)
+	t.pixels = img
+	t.size = Vector2{float32(img.Bounds().Dx()), float32(img.Bounds().Dy())}
+	t.pixelsChanged = true
+
+	// Force the render targets to bind the texture again
+	t.cacheId = nextTextureCacheId()
+}
+
 // Utilities ###################################################################
 
+// flipVertically reverses the order of the rows of an image
+func flipVertically(img *image.NRGBA) {
+	stride := img.Stride
+	row := make([]uint8, stride)
+	for top, bottom := 0, img.Rect.Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		topRow := img.Pix[top*stride : (top+1)*stride]
+		bottomRow := img.Pix[bottom*stride : (bottom+1)*stride]
+		copy(row, topRow)
+		copy(topRow, bottomRow)
+		copy(bottomRow, row)
+	}
+}
+
+// CreateTexture creates a texture from an image of any type. The pixels are
+// copied, converted to NRGBA if needed: changing the image afterwards doesn't
+// change the texture, use Update for that.
+//
+// The copy is kept in memory until the texture is first drawn with OpenGL,
+// which uploads it and releases it. From then on, Update and CopyToImage go
+// through OpenGL, and the software backend draws the texture as if no texture
+// was set.
 func CreateTexture(img image.Image) (*Texture, error) {
-	imgW, imgH := img.Bounds().Dx(), img.Bounds().Dy()
-	imgDim := Vector2{float32(imgW), float32(imgH)}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, errors.New("sf: texture image is empty")
+	}
 
-	rgbaImg, ok := img.(*image.NRGBA)
-	if !ok {
-		return nil, errors.New("texture must be an NRGBA image")
+	// The pixels are uploaded as is, they're tightly packed from the origin
+	pixels := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(pixels, pixels.Rect, img, bounds.Min, draw.Src)
+	return newTexture(pixels), nil
+}
+
+// newTexture creates a texture holding pixels, without copying them. They must
+// be tightly packed from the origin, and changes to them must be reported with
+// invalidate.
+func newTexture(pixels *image.NRGBA) *Texture {
+	// The OpenGL texture is created lazily, so textures can be created (and
+	// drawn by the software backend) without any GL context
+	t := &Texture{size: Vector2{float32(pixels.Rect.Dx()), float32(pixels.Rect.Dy())}, pixels: pixels,
+		cacheId: nextTextureCacheId()}
+	if textureFinalizers {
+		t.setFinalizer(true)
+	}
+	return t
+}
+
+// Textures garbage collected without being destroyed, waiting to be deleted
+var texturesToDelete struct {
+	sync.Mutex
+	names []gl.Texture
+}
+
+// Are finalizers attached to the new textures?
+var textureFinalizers bool
+
+// SetTextureFinalizers enables or disables the deletion of the OpenGL
+// textures created afterwards, once they're garbage collected. Finalizers run
+// on their own goroutine, without an OpenGL context: the deletion is deferred
+// to the next time a render target draws on the rendering thread. Destroy
+// frees the texture right away, it's the better option when possible.
+func SetTextureFinalizers(enabled bool) {
+	textureFinalizers = enabled
+}
+
+// setFinalizer attaches or detaches the finalizer deleting the OpenGL texture
+func (t *Texture) setFinalizer(enabled bool) {
+	t.hasFinalizer = enabled
+	if enabled {
+		runtime.SetFinalizer(t, finalizeTexture)
+	} else {
+		runtime.SetFinalizer(t, nil)
 	}
+}
 
-	textureId := gl.GenTexture()
-	textureId.Bind(gl.TEXTURE_2D)
-	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
-	gl.TexParameterf(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+func finalizeTexture(t *Texture) {
+	if t.t != 0 {
+		texturesToDelete.Lock()
+		texturesToDelete.names = append(texturesToDelete.names, t.t)
+		texturesToDelete.Unlock()
+	}
+}
 
-	gl.TexImage2D(gl.TEXTURE_2D, 0, 4, imgW, imgH, 0, gl.RGBA, gl.UNSIGNED_BYTE, rgbaImg.Pix)
+// deleteFinalizedTextures deletes the textures queued by the finalizers. It
+// must be called on the rendering thread, with an OpenGL context current.
+func deleteFinalizedTextures() {
+	texturesToDelete.Lock()
+	names := texturesToDelete.names
+	texturesToDelete.names = nil
+	texturesToDelete.Unlock()
 
-	return &Texture{textureId, imgDim, false, false, false, nextTextureCacheId()}, nil
+	for _, name := range names {
+		name.Delete()
+	}
 }
 
 // Unique cache id generator
@@ -0,0 +1,385 @@
+package sf
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-gl-legacy/gl"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestCreateTextureConversion(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	rgba.SetRGBA(1, 0, color.RGBA{128, 0, 0, 128})
+
+	paletted := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.Black, color.White})
+	paletted.SetColorIndex(1, 0, 1)
+
+	gray := image.NewGray(image.Rect(0, 0, 2, 2))
+	gray.SetGray(1, 0, color.Gray{255})
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	nrgba.SetNRGBA(2, 1, color.NRGBA{1, 2, 3, 4})
+	sub := nrgba.SubImage(image.Rect(1, 1, 3, 3))
+
+	tests := []struct {
+		img  image.Image
+		want color.NRGBA
+	}{
+		{rgba, color.NRGBA{255, 0, 0, 128}},
+		{paletted, color.NRGBA{255, 255, 255, 255}},
+		{gray, color.NRGBA{255, 255, 255, 255}},
+		{sub, color.NRGBA{1, 2, 3, 4}},
+	}
+	for _, test := range tests {
+		tex, err := CreateTexture(test.img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tex.Size() != (Vector2{2, 2}) || tex.pixels.Rect.Min != (image.Point{}) {
+			t.Errorf("%T: size %v", test.img, tex.Size())
+		}
+		if got := tex.pixels.NRGBAAt(1, 0); got != test.want {
+			t.Errorf("%T: pixel = %v, want %v", test.img, got, test.want)
+		}
+	}
+
+	if _, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Error("empty image accepted")
+	}
+}
+
+func TestLoadTexture(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	encoders := map[string]func(io.Writer, image.Image) error{
+		"png":  png.Encode,
+		"bmp":  bmp.Encode,
+		"jpg":  func(w io.Writer, m image.Image) error { return jpeg.Encode(w, m, nil) },
+		"gif":  func(w io.Writer, m image.Image) error { return gif.Encode(w, m, nil) },
+		"none": func(w io.Writer, m image.Image) error { _, err := w.Write([]byte("not an image")); return err },
+	}
+
+	dir := t.TempDir()
+	for ext, encode := range encoders {
+		var buf bytes.Buffer
+		if err := encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		fname := filepath.Join(dir, "img."+ext)
+		if err := os.WriteFile(fname, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		tex, err := LoadTexture(fname)
+		if ext == "none" {
+			var texErr *TextureError
+			if !errors.As(err, &texErr) || texErr.Stage != "decode" || texErr.Path != fname {
+				t.Errorf("invalid image: %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", ext, err)
+		} else if tex.Size() != (Vector2{3, 2}) {
+			t.Errorf("%s: size %v", ext, tex.Size())
+		}
+	}
+
+	missing := filepath.Join(dir, "missing.png")
+	if _, err := LoadTexture(missing); !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), missing) {
+		t.Errorf("missing file: %v", err)
+	}
+
+	// The deprecated wrapper keeps its old signature and panics on errors
+	if tex := NewTextureFromFile(filepath.Join(dir, "img.png")); tex.Size() != (Vector2{3, 2}) {
+		t.Errorf("NewTextureFromFile: size %v", tex.Size())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTextureFromFile didn't panic on a missing file")
+		}
+	}()
+	NewTextureFromFile(missing)
+}
+
+func TestLoadTextureFromMemory(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatal(err)
+	}
+
+	tex, err := LoadTextureFromMemory(buf.Bytes())
+	if err != nil || tex.Size() != (Vector2{4, 3}) {
+		t.Errorf("from memory: %v", err)
+	}
+
+	tex, err = LoadTextureFromReader(bytes.NewReader(buf.Bytes()))
+	if err != nil || tex.Size() != (Vector2{4, 3}) {
+		t.Errorf("from reader: %v", err)
+	}
+
+	fsys := fstest.MapFS{"assets/img.png": {Data: buf.Bytes()}}
+	tex, err = LoadTextureFromFS(fsys, "assets/img.png")
+	if err != nil || tex.Size() != (Vector2{4, 3}) {
+		t.Errorf("from fs: %v", err)
+	}
+
+	var texErr *TextureError
+	if _, err := LoadTextureFromFS(fsys, "assets/missing.png"); !errors.As(err, &texErr) || texErr.Stage != "open" {
+		t.Errorf("missing file in fs: %v", err)
+	}
+	if _, err := LoadTextureFromMemory(buf.Bytes()[:20]); !errors.As(err, &texErr) || texErr.Stage != "decode" {
+		t.Errorf("truncated image: %v", err)
+	}
+}
+
+func TestTextureUpdate(t *testing.T) {
+	tex, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 4, 4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch := image.NewRGBA(image.Rect(10, 10, 12, 11))
+	patch.SetRGBA(11, 10, color.RGBA{255, 0, 0, 255})
+	id := tex.cacheId
+	if err := tex.Update(patch, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if tex.dirty != image.Rect(2, 3, 4, 4) || tex.cacheId == id {
+		t.Errorf("dirty region: %v", tex.dirty)
+	}
+	if err := tex.Update(patch, 3, 3); err == nil {
+		t.Error("update out of bounds accepted")
+	}
+
+	// The image the texture was created from is left alone
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	shared, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shared.Update(patch, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if src.NRGBAAt(3, 3) != (color.NRGBA{}) || shared.pixels.NRGBAAt(3, 3) != (color.NRGBA{255, 0, 0, 255}) {
+		t.Error("update changed the source image")
+	}
+
+	img := tex.CopyToImage()
+	if img.NRGBAAt(3, 3) != (color.NRGBA{255, 0, 0, 255}) || img.NRGBAAt(2, 3) != (color.NRGBA{}) {
+		t.Error("update not applied")
+	}
+	img.SetNRGBA(0, 0, color.NRGBA{1, 1, 1, 1})
+	if tex.pixels.NRGBAAt(0, 0) == img.NRGBAAt(0, 0) {
+		t.Error("CopyToImage doesn't copy")
+	}
+
+	// Copy keeps the pixels and settings
+	tex.SetSmooth(true)
+	c, err := tex.Copy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.IsSmooth() || c.pixels == tex.pixels || c.CopyToImage().NRGBAAt(3, 3) != (color.NRGBA{255, 0, 0, 255}) {
+		t.Error("copy differs")
+	}
+
+	// Swap exchanges everything
+	other, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 2, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := [2]uint64{tex.cacheId, other.cacheId}
+	tex.Swap(other)
+	if tex.Size() != (Vector2{2, 1}) || other.Size() != (Vector2{4, 4}) || tex.IsSmooth() || !other.IsSmooth() {
+		t.Error("textures not swapped")
+	}
+	if tex.cacheId == ids[0] || tex.cacheId == ids[1] || other.cacheId == ids[0] || other.cacheId == ids[1] {
+		t.Error("cache ids not renewed")
+	}
+}
+
+func TestFlipVertically(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 3))
+	for y := 0; y < 3; y++ {
+		img.SetNRGBA(0, y, color.NRGBA{uint8(y), 0, 0, 255})
+	}
+	flipVertically(img)
+	for y := 0; y < 3; y++ {
+		if got := img.NRGBAAt(0, y).R; got != uint8(2-y) {
+			t.Errorf("row %d = %d", y, got)
+		}
+	}
+}
+
+func TestTextureDestroy(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	tex, err := CreateTexture(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, img := newSoftwareTarget(2, 1)
+	target.Clear(Color{0, 0, 0, 255})
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex}
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+
+	// A destroyed texture is drawn as no texture
+	tex.Destroy()
+	if tex.pixels != nil || tex.Size() != (Vector2{}) {
+		t.Error("texture not released")
+	}
+	target.Render(quad(1, 0, 1, 1, Color{0, 255, 0, 255}), Quads, states)
+
+	if img.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || img.RGBAAt(1, 0) != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("pixels: %v, %v", img.RGBAAt(0, 0), img.RGBAAt(1, 0))
+	}
+}
+
+func TestTextureFinalizer(t *testing.T) {
+	SetTextureFinalizers(true)
+	defer SetTextureFinalizers(false)
+
+	tex, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pretend it was uploaded, the finalizer must only queue it
+	tex.t = 42
+	finalizeTexture(tex)
+	texturesToDelete.Lock()
+	queued := texturesToDelete.names
+	texturesToDelete.names = nil
+	texturesToDelete.Unlock()
+	if len(queued) != 1 || queued[0] != 42 {
+		t.Errorf("queued textures: %v", queued)
+	}
+	tex.t = 0
+}
+
+func TestTextureSwapFinalizers(t *testing.T) {
+	plain, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetTextureFinalizers(true)
+	defer SetTextureFinalizers(false)
+	finalized, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 2, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The finalizer follows the OpenGL texture it deletes
+	plain.Swap(finalized)
+	if !plain.hasFinalizer || finalized.hasFinalizer || plain.Size() != (Vector2{2, 2}) {
+		t.Error("finalizer not swapped with the contents")
+	}
+
+	finalized.Destroy()
+	if finalized.hasFinalizer {
+		t.Error("finalizer kept by a destroyed texture")
+	}
+}
+
+func TestDeleteFinalizedTextures(t *testing.T) {
+	target := newGLTarget(t)
+	SetTextureFinalizers(true)
+	defer SetTextureFinalizers(false)
+
+	tex, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex}
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+	if tex.t == 0 {
+		t.Fatal("texture not uploaded")
+	}
+
+	// The queue is emptied by the next draw
+	finalizeTexture(tex)
+	tex.t = 0
+	target.Clear(Color{0, 0, 0, 255})
+	texturesToDelete.Lock()
+	queued := len(texturesToDelete.names)
+	texturesToDelete.Unlock()
+	if queued != 0 {
+		t.Errorf("%d textures still queued", queued)
+	}
+	if err := gl.GetError(); err != gl.NO_ERROR {
+		t.Errorf("OpenGL error %#x", err)
+	}
+}
+
+func TestTextureDestroyGL(t *testing.T) {
+	target := newGLTarget(t)
+
+	tex, err := CreateTexture(image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex}
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+
+	// Drawing a destroyed texture binds no texture
+	tex.Destroy()
+	target.Render(quad(0, 0, 1, 1, Color{255, 255, 255, 255}), Quads, states)
+	if target.lastTextureId != 0 {
+		t.Error("destroyed texture still bound")
+	}
+	if err := gl.GetError(); err != gl.NO_ERROR {
+		t.Errorf("OpenGL error %#x", err)
+	}
+}
+
+func TestTexturePixelsReleased(t *testing.T) {
+	target := newGLTarget(t)
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.SetNRGBA(1, 0, color.NRGBA{255, 0, 0, 255})
+	tex, err := CreateTexture(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	font := NewFontFromFace(basicfont.Face7x13)
+	font.Glyph('a', 13, false)
+	page := font.Texture(13)
+
+	states := RenderStates{BlendMode: BlendAlpha(), Transform: IdentityTransform(), Texture: tex}
+	target.Render(quad(0, 0, 2, 2, Color{255, 255, 255, 255}), Quads, states)
+	states.Texture = page
+	target.Render(quad(0, 0, 2, 2, Color{255, 255, 255, 255}), Quads, states)
+
+	// Only OpenGL keeps the pixels once uploaded, except for the font pages
+	if tex.pixels != nil || page.pixels == nil {
+		t.Errorf("pixels kept: %v, font page: %v", tex.pixels != nil, page.pixels != nil)
+	}
+	if got := tex.CopyToImage(); !bytes.Equal(got.Pix, img.Pix) {
+		t.Errorf("pixels read back: %v", got.Pix)
+	}
+	if err := tex.Update(image.NewNRGBA(image.Rect(0, 0, 1, 1)), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := tex.CopyToImage(); got.NRGBAAt(1, 0) != (color.NRGBA{}) {
+		t.Errorf("update after the release: %v", got.NRGBAAt(1, 0))
+	}
+}
@@ -1,5 +1,8 @@
 package sf
 
+// Vertex is a point with a color and texture coordinates. Its memory layout
+// matches what OpenGL's vertex arrays expect (two floats, four bytes, two
+// floats, tightly packed), so slices of vertices are handed to OpenGL as-is.
 type Vertex struct {
 	Pos       Vector2 // 2D position of the vertex
 	Color     Color   // Color of the vertex
@@ -0,0 +1,86 @@
+package sf
+
+// VertexArray is a set of vertices drawn as one primitive type, e.g. the
+// quads of a tilemap or the points of a particle system. Unlike a
+// VertexBuffer, the vertices stay on the CPU and are sent at every draw.
+type VertexArray struct {
+	verts    []Vertex      // Vertices of the array
+	primType PrimitiveType // Type of primitives the vertices are drawn as
+}
+
+// NewVertexArray creates a vertex array holding count vertices, all zero
+func NewVertexArray(primType PrimitiveType, count int) *VertexArray {
+	return &VertexArray{make([]Vertex, count), primType}
+}
+
+func (va *VertexArray) VertexCount() int {
+	return len(va.verts)
+}
+
+// Vertex returns the index-th vertex, to be read or modified in place
+func (va *VertexArray) Vertex(index int) *Vertex {
+	return &va.verts[index]
+}
+
+// Vertices returns the vertices of the array. The slice stays valid until
+// the array is resized or appended to.
+func (va *VertexArray) Vertices() []Vertex {
+	return va.verts
+}
+
+func (va *VertexArray) SetPrimitiveType(primType PrimitiveType) {
+	va.primType = primType
+}
+
+func (va *VertexArray) PrimitiveType() PrimitiveType {
+	return va.primType
+}
+
+// Append adds vertices at the end of the array
+func (va *VertexArray) Append(verts ...Vertex) {
+	va.verts = append(va.verts, verts...)
+}
+
+// Resize changes the number of vertices. New vertices are zero.
+func (va *VertexArray) Resize(count int) {
+	if count <= cap(va.verts) {
+		old := len(va.verts)
+		va.verts = va.verts[:count]
+		for i := old; i < count; i++ {
+			va.verts[i] = Vertex{}
+		}
+		return
+	}
+
+	grown := make([]Vertex, count)
+	copy(grown, va.verts)
+	va.verts = grown
+}
+
+// Clear removes all the vertices, keeping the memory allocated for reuse
+func (va *VertexArray) Clear() {
+	va.verts = va.verts[:0]
+}
+
+// Bounds returns the bounding rectangle of the positions of the vertices
+func (va *VertexArray) Bounds() Rect {
+	if len(va.verts) == 0 {
+		return Rect{}
+	}
+
+	left, top := va.verts[0].Pos.X, va.verts[0].Pos.Y
+	right, bottom := left, top
+	for _, v := range va.verts[1:] {
+		left = min32(left, v.Pos.X)
+		top = min32(top, v.Pos.Y)
+		right = max32(right, v.Pos.X)
+		bottom = max32(bottom, v.Pos.Y)
+	}
+	return Rect{left, top, right - left, bottom - top}
+}
+
+func (va *VertexArray) Render(target *RenderTarget, states RenderStates) {
+	if len(va.verts) > 0 {
+		target.Render(va.verts, va.primType, states)
+	}
+}
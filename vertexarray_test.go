@@ -0,0 +1,51 @@
+package sf
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestVertexArray(t *testing.T) {
+	va := NewVertexArray(Points, 2)
+	va.Vertex(0).Pos = Vector2{2, 5}
+	va.Vertex(1).Pos = Vector2{-1, 3}
+	va.Append(Vertex{Pos: Vector2{4, 4}}, Vertex{Pos: Vector2{0, 8}})
+	if va.VertexCount() != 4 || va.Bounds() != (Rect{-1, 3, 5, 5}) {
+		t.Errorf("%d vertices, bounds %v", va.VertexCount(), va.Bounds())
+	}
+
+	// Shrinking then growing again must not bring back old vertices
+	va.Resize(1)
+	va.Resize(3)
+	if va.VertexCount() != 3 || *va.Vertex(1) != (Vertex{}) || va.Vertex(0).Pos != (Vector2{2, 5}) {
+		t.Errorf("resized vertices: %v", va.Vertices())
+	}
+	va.Resize(10)
+	if va.VertexCount() != 10 || va.Vertex(0).Pos != (Vector2{2, 5}) {
+		t.Errorf("grown vertices: %v", va.Vertices())
+	}
+
+	va.Clear()
+	if va.VertexCount() != 0 || va.Bounds() != (Rect{}) {
+		t.Error("vertices not cleared")
+	}
+}
+
+func TestVertexArrayDraw(t *testing.T) {
+	target, img := newSoftwareTarget(8, 8)
+	target.Clear(Color{0, 0, 0, 255})
+
+	va := NewVertexArray(Quads, 0)
+	va.Append(quad(0, 0, 2, 2, Color{255, 255, 255, 255})...)
+	va.Append(quad(4, 4, 3, 1, Color{255, 255, 255, 255})...)
+	target.Draw(va)
+
+	va.SetPrimitiveType(Points)
+	if va.PrimitiveType() != Points {
+		t.Error("primitive type not set")
+	}
+
+	if got := countPixels(img, color.RGBA{255, 255, 255, 255}); got != 7 {
+		t.Errorf("%d pixels drawn, want 7", got)
+	}
+}
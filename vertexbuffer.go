@@ -0,0 +1,105 @@
+package sf
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/go-gl-legacy/gl"
+)
+
+// Usage hint of a VertexBuffer, telling how often its vertices change
+type VertexBufferUsage uint8
+
+const (
+	UsageStatic  VertexBufferUsage = iota // Set once, drawn many times
+	UsageDynamic                          // Updated from time to time, drawn many times
+	UsageStream                           // Updated about every time it's drawn
+)
+
+// VertexBuffer stores vertices on the GPU, so that static geometry is
+// uploaded once instead of at every draw. It's drawn with
+// RenderTarget.RenderBuffer.
+type VertexBuffer struct {
+	verts  []Vertex          // Vertices, also kept on the CPU side for the software backend
+	usage  VertexBufferUsage // How often the vertices change
+	buffer gl.Buffer         // OpenGL buffer, created on first draw
+	size   int               // Number of vertices allocated in the OpenGL buffer
+
+	dirtyFrom int // First vertex that changed since the last upload
+	dirtyTo   int // One past the last vertex that changed since the last upload
+}
+
+// NewVertexBuffer creates a vertex buffer holding count vertices, all zero
+func NewVertexBuffer(count int, usage VertexBufferUsage) *VertexBuffer {
+	return &VertexBuffer{verts: make([]Vertex, count), usage: usage}
+}
+
+func (vb *VertexBuffer) VertexCount() int {
+	return len(vb.verts)
+}
+
+func (vb *VertexBuffer) Usage() VertexBufferUsage {
+	return vb.usage
+}
+
+// Update replaces the vertices starting at offset. The buffer grows if they
+// go past its end.
+func (vb *VertexBuffer) Update(offset int, verts []Vertex) error {
+	if offset < 0 || offset > len(vb.verts) {
+		return errors.New("sf: vertex buffer update offset out of range")
+	}
+
+	if end := offset + len(verts); end > len(vb.verts) {
+		grown := make([]Vertex, end)
+		copy(grown, vb.verts)
+		vb.verts = grown
+	}
+	copy(vb.verts[offset:], verts)
+
+	// Remember which vertices must be uploaded
+	if vb.dirtyFrom == vb.dirtyTo {
+		vb.dirtyFrom, vb.dirtyTo = offset, offset+len(verts)
+	} else {
+		if offset < vb.dirtyFrom {
+			vb.dirtyFrom = offset
+		}
+		if offset+len(verts) > vb.dirtyTo {
+			vb.dirtyTo = offset + len(verts)
+		}
+	}
+
+	return nil
+}
+
+// Destroy deletes the OpenGL buffer. The vertex buffer can't be used anymore.
+func (vb *VertexBuffer) Destroy() {
+	if vb.buffer != 0 {
+		vb.buffer.Delete()
+		vb.buffer = 0
+	}
+	vb.verts = nil
+	vb.size = 0
+}
+
+// upload sends the vertices that changed to the OpenGL buffer, creating or
+// growing it if needed
+func (vb *VertexBuffer) upload() {
+	stride := int(unsafe.Sizeof(Vertex{}))
+	usages := [...]gl.GLenum{gl.STATIC_DRAW, gl.DYNAMIC_DRAW, gl.STREAM_DRAW}
+
+	if vb.buffer == 0 {
+		vb.buffer = gl.GenBuffer()
+	}
+	vb.buffer.Bind(gl.ARRAY_BUFFER)
+
+	if vb.size != len(vb.verts) {
+		gl.BufferData(gl.ARRAY_BUFFER, len(vb.verts)*stride, vb.verts, usages[vb.usage])
+		vb.size = len(vb.verts)
+	} else if vb.dirtyFrom < vb.dirtyTo {
+		gl.BufferSubData(gl.ARRAY_BUFFER, vb.dirtyFrom*stride, (vb.dirtyTo-vb.dirtyFrom)*stride,
+			vb.verts[vb.dirtyFrom:vb.dirtyTo])
+	}
+	vb.dirtyFrom, vb.dirtyTo = 0, 0
+
+	vb.buffer.Unbind(gl.ARRAY_BUFFER)
+}
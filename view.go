@@ -137,3 +137,8 @@ func (v *View) InverseTransform() Transform {
 
 	return v.invTransform
 }
+
+// equals tells if two views show the same area in the same viewport
+func (v *View) equals(v2 *View) bool {
+	return v.center == v2.center && v.size == v2.size && v.rot == v2.rot && v.viewport == v2.viewport
+}
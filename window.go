@@ -0,0 +1,189 @@
+package sf
+
+import (
+	"github.com/go-gl/glfw3/v3.1/glfw"
+)
+
+// RenderWindow is a window that can be drawn into like any RenderTarget.
+// Its events are queued and retrieved with PollEvent or WaitEvent.
+type RenderWindow struct {
+	*RenderTarget
+
+	window  *glfw.Window
+	backend *glBackend // Backend drawing into the window
+	events  []Event    // Events received but not polled yet
+}
+
+// Windows not closed yet, Terminate closes them
+var openWindows = make(map[*RenderWindow]struct{})
+
+// NewRenderWindow opens a window whose client area is width x height and
+// makes its OpenGL context current. Init must have been called.
+func NewRenderWindow(width, height int, title string) (*RenderWindow, error) {
+	// Share the textures, shaders, etc. with the other contexts
+	share, err := sharedContext()
+	if err != nil {
+		return nil, err
+	}
+
+	// The stencil buffer holds the masks drawn with DrawMask
+	glfw.WindowHint(glfw.StencilBits, 8)
+	window, err := glfw.CreateWindow(width, height, title, nil, share)
+	glfw.DefaultWindowHints()
+	if err != nil {
+		return nil, err
+	}
+	window.MakeContextCurrent()
+
+	w := &RenderWindow{window: window, backend: &glBackend{window: window}}
+	fbW, fbH := window.GetFramebufferSize()
+	w.RenderTarget = NewRenderTargetWithBackend(Vector2{float32(fbW), float32(fbH)}, w.backend)
+	w.setCallbacks()
+	openWindows[w] = struct{}{}
+
+	return w, nil
+}
+
+// IsOpen tells if the window is open, i.e. Close hasn't been called
+func (w *RenderWindow) IsOpen() bool {
+	return w.window != nil
+}
+
+// Close closes the window and destroys its context. Drawing into the window
+// afterwards does nothing.
+func (w *RenderWindow) Close() {
+	if w.window != nil {
+		w.Flush()
+		w.window.Destroy()
+		w.window = nil
+		w.events = nil
+		delete(openWindows, w)
+
+		// The backend mustn't use the destroyed context anymore
+		w.backend.window = nil
+		w.RenderTarget.backend = closedBackend{}
+		invalidateGLStates()
+	}
+}
+
+// Display shows on screen what has been drawn into the window so far
+func (w *RenderWindow) Display() {
+	w.Flush()
+	if w.window != nil {
+		w.window.SwapBuffers()
+	}
+}
+
+func (w *RenderWindow) SetTitle(title string) {
+	if w.window != nil {
+		w.window.SetTitle(title)
+	}
+}
+
+// SetVerticalSyncEnabled synchronizes Display with the refresh rate of the
+// monitor when enabled
+func (w *RenderWindow) SetVerticalSyncEnabled(enabled bool) {
+	if w.window == nil {
+		return
+	}
+	w.window.MakeContextCurrent()
+	if enabled {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+}
+
+// PollEvent returns the next pending event, if there's one. It never blocks.
+func (w *RenderWindow) PollEvent() (Event, bool) {
+	if len(w.events) == 0 && w.window != nil {
+		glfw.PollEvents()
+	}
+	return w.popEvent()
+}
+
+// WaitEvent blocks until an event is received and returns it. It returns
+// false if the window is closed.
+func (w *RenderWindow) WaitEvent() (Event, bool) {
+	for len(w.events) == 0 && w.window != nil {
+		glfw.WaitEvents()
+	}
+	return w.popEvent()
+}
+
+func (w *RenderWindow) popEvent() (Event, bool) {
+	if len(w.events) == 0 {
+		return nil, false
+	}
+	event := w.events[0]
+	w.events = w.events[1:]
+	return event, true
+}
+
+func (w *RenderWindow) pushEvent(event Event) {
+	w.events = append(w.events, event)
+}
+
+// cursorPos returns the position of the mouse cursor relative to the window
+func (w *RenderWindow) cursorPos() (int, int) {
+	x, y := w.window.GetCursorPos()
+	return int(x), int(y)
+}
+
+// setCallbacks converts the glfw callbacks into queued events
+func (w *RenderWindow) setCallbacks() {
+	w.window.SetCloseCallback(func(*glfw.Window) {
+		w.pushEvent(ClosedEvent{})
+	})
+
+	w.window.SetFramebufferSizeCallback(func(_ *glfw.Window, width, height int) {
+		// A minimized window has an empty framebuffer, keep the views as they are
+		if width > 0 && height > 0 {
+			w.SetSize(Vector2{float32(width), float32(height)})
+		}
+		w.pushEvent(ResizedEvent{width, height})
+	})
+
+	w.window.SetFocusCallback(func(_ *glfw.Window, focused bool) {
+		if focused {
+			w.pushEvent(FocusGainedEvent{})
+		} else {
+			w.pushEvent(FocusLostEvent{})
+		}
+	})
+
+	w.window.SetCharCallback(func(_ *glfw.Window, char rune) {
+		w.pushEvent(TextEnteredEvent{char})
+	})
+
+	w.window.SetKeyCallback(func(_ *glfw.Window, key glfw.Key, scancode int, action glfw.Action,
+		mods glfw.ModifierKey) {
+		e := KeyEvent{Key(key), scancode, mods&glfw.ModAlt != 0, mods&glfw.ModControl != 0,
+			mods&glfw.ModShift != 0, mods&glfw.ModSuper != 0}
+		if action == glfw.Release {
+			w.pushEvent(KeyReleasedEvent{e})
+		} else {
+			w.pushEvent(KeyPressedEvent{e})
+		}
+	})
+
+	w.window.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
+		w.pushEvent(MouseMovedEvent{int(x), int(y)})
+	})
+
+	w.window.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action,
+		mods glfw.ModifierKey) {
+		x, y := w.cursorPos()
+		e := MouseButtonEvent{MouseButton(button), x, y}
+		if action == glfw.Press {
+			w.pushEvent(MouseButtonPressedEvent{e})
+		} else {
+			w.pushEvent(MouseButtonReleasedEvent{e})
+		}
+	})
+
+	w.window.SetScrollCallback(func(_ *glfw.Window, xoff, yoff float64) {
+		x, y := w.cursorPos()
+		w.pushEvent(MouseWheelEvent{float32(xoff), float32(yoff), x, y})
+	})
+}
@@ -0,0 +1,49 @@
+package sf
+
+import (
+	"testing"
+)
+
+func TestDrawClosedWindow(t *testing.T) {
+	if err := Init(); err != nil {
+		t.Skip(err)
+	}
+	defer Terminate()
+	w, err := NewRenderWindow(64, 64, "test")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	w.SetBatching(true)
+	w.Render(quad(0, 0, 8, 8, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+	w.Close()
+	if w.IsOpen() {
+		t.Fatal("window still open")
+	}
+
+	// Drawing into a closed window does nothing
+	w.Clear(Color{0, 0, 0, 255})
+	w.Render(quad(0, 0, 8, 8, Color{255, 255, 255, 255}), Quads, DefaultRenderStates())
+	w.Display()
+	if w.backend.window != nil || activeGLBackend == w.backend {
+		t.Error("closed window still used by the backend")
+	}
+}
+
+func TestTerminateClosesWindows(t *testing.T) {
+	if err := Init(); err != nil {
+		t.Skip(err)
+	}
+	w, err := NewRenderWindow(64, 64, "test")
+	if err != nil {
+		Terminate()
+		t.Skip(err)
+	}
+
+	Terminate()
+	if w.IsOpen() || len(openWindows) != 0 {
+		t.Fatal("window left open by Terminate")
+	}
+	w.Clear(Color{0, 0, 0, 255})
+	w.Display()
+}